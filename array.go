@@ -0,0 +1,48 @@
+package queryparser
+
+import (
+	"net/url"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+)
+
+// mergeArrayFields merge values of fields with typemapper.ArraySuffix
+// to values of field without it if ParseSchema item of that field
+// have Mapper that accept array suffix
+//
+// Values of field without suffix go first
+func (p *Parser) mergeArrayFields(schema *compiledSchema, urlValues url.Values) url.Values {
+	var merged url.Values
+
+	for field, values := range urlValues {
+		name, ok := typemapper.TrimArraySuffix(field)
+		if !ok {
+			continue
+		} else if _, err := p.findField(schema, field); err == nil {
+			continue
+		}
+
+		entry, err := p.findField(schema, name)
+		if err != nil {
+			continue
+		} else if mapper, ok := entry.Item.Mapper.(typemapper.ArrayQueryTypeMapper); !ok || !mapper.AcceptArraySuffix() {
+			continue
+		}
+
+		if merged == nil {
+			merged = make(url.Values, len(urlValues))
+			for field, values := range urlValues {
+				merged[field] = values
+			}
+		}
+
+		delete(merged, field)
+		merged[name] = append(append([]string(nil), urlValues[name]...), values...)
+	}
+
+	if merged == nil {
+		return urlValues
+	}
+
+	return merged
+}
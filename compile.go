@@ -0,0 +1,212 @@
+package queryparser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+)
+
+// SchemaError describe bad key of ParseSchema
+type SchemaError struct {
+	Key string
+	Err error
+}
+
+func (s *SchemaError) Error() string {
+	return fmt.Sprintf("Bad schema key %q: %v", s.Key, s.Err)
+}
+
+func (s *SchemaError) Unwrap() error {
+	return s.Err
+}
+
+// SchemaErrors is collection of bad keys of ParseSchema
+type SchemaErrors []*SchemaError
+
+func (s SchemaErrors) Error() string {
+	messages := make([]string, len(s))
+	for i, err := range s {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// compiledSchema is ParseSchema with compiled regex keys
+type compiledSchema struct {
+	// regex entries in order of matching and then direct entries
+	entries []compiledEntry
+
+	direct ParseSchema
+
+	// factory where schema is registered
+	factory Factory
+
+	// parsers of Nested schemas by keys
+	nested map[string]*Parser
+}
+
+type compiledEntry struct {
+	ParseSchemaEntry
+
+	// nil for direct entries
+	regex *regexp.Regexp
+}
+
+// compileSchema compile regex keys of schema,
+// entries with bad keys are skipped and reported in SchemaErrors
+func compileSchema(schema ParseSchema) (*compiledSchema, error) {
+	var (
+		compiled = &compiledSchema{direct: schema}
+		errs     SchemaErrors
+	)
+
+	for _, entry := range schema.ordered() {
+		if !entry.Item.IsRegex {
+			compiled.entries = append(compiled.entries, compiledEntry{ParseSchemaEntry: entry})
+			continue
+		}
+
+		regex, err := regexp.Compile(pattern(entry.Key, entry.Item))
+		if err != nil {
+			errs = append(errs, &SchemaError{Key: entry.Key, Err: err})
+			continue
+		}
+
+		compiled.entries = append(
+			compiled.entries,
+			compiledEntry{
+				ParseSchemaEntry: entry,
+				regex:            regex,
+			},
+		)
+	}
+
+	if len(errs) > 0 {
+		return compiled, errs
+	}
+
+	return compiled, nil
+}
+
+// pattern return regex pattern of key that match whole field name
+// if PartialMatch of item is false
+func pattern(key string, item ParseSchemaItem) string {
+	if item.PartialMatch {
+		return key
+	}
+
+	return `^(?:` + key + `)$`
+}
+
+// Compile validate regex keys of ParseSchema,
+// register schema items and keep compiled matchers
+//
+// If Factory have Compile method it also called
+//
+// Compile should be called again after ParseSchema changes
+func (p *Parser) Compile() error {
+	compiled, err := compileSchema(p.ParseSchema)
+	if err != nil {
+		return err
+	}
+
+	p.registerSchema(compiled)
+
+	if err := p.compileNested(compiled); err != nil {
+		return err
+	}
+
+	if compiler, ok := compiled.factory.(interface{ Compile() error }); ok {
+		if err := compiler.Compile(); err != nil {
+			return err
+		}
+	}
+
+	p.compiled = compiled
+	return nil
+}
+
+// MustCompile same as Compile but panic on error
+func (p *Parser) MustCompile() *Parser {
+	if err := p.Compile(); err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// schema return compiled schema
+// or compile it if Compile was not called
+func (p *Parser) schema() *compiledSchema {
+	if p.compiled != nil {
+		return p.compiled
+	}
+
+	compiled, _ := compileSchema(p.ParseSchema)
+	p.registerSchema(compiled)
+	p.compileNested(compiled)
+
+	return compiled
+}
+
+// registerSchema add schema items to clone of Factory
+// or to Factory itself if it can't be cloned,
+// regex items are added first in order of matching
+func (p *Parser) registerSchema(schema *compiledSchema) {
+	schema.factory = p.Factory
+	if cloner, ok := p.Factory.(interface {
+		Clone() *typemapper.QueryTypeFactory
+	}); ok {
+		schema.factory = cloner.Clone()
+	}
+
+	for _, entry := range schema.entries {
+		if !entry.Item.hasMapper() {
+			continue
+		}
+		schema.factory.AddQueryTypeMapperField(entry.Key, entry.mapper())
+	}
+}
+
+// mapper build QueryTypeMapper from item,
+// captures funcs take named captures of compiled key
+func (c *compiledEntry) mapper() typemapper.QueryTypeMapper {
+	var (
+		item           = c.Item
+		typeMap        = item.TypeMapFunc
+		contextTypeMap = item.TypeMapContextFunc
+		validateField  = item.ValidateFieldFunc
+		validateValues = item.ValidateValuesFunc
+	)
+
+	if item.TypeMapCapturesFunc != nil {
+		typeMap = item.TypeMapCapturesFunc.WithCaptures(c.regex)
+	}
+
+	if item.ValidateFieldCapturesFunc != nil {
+		validateField = typemapper.MergeValidateFieldFunc(
+			item.ValidateFieldFunc,
+			item.ValidateFieldCapturesFunc.WithCaptures(c.regex),
+		)
+	}
+
+	if mapper := item.Mapper; mapper != nil {
+		validateField = typemapper.MergeValidateFieldFunc(mapper.ValidateField, validateField)
+		validateValues = typemapper.MergeValidateValuesFunc(mapper.ValidateValues, validateValues)
+		typeMap = mapper.Map
+		contextTypeMap = func(ctx context.Context, field string, values []string) (interface{}, error) {
+			return typemapper.MapContext(ctx, mapper, field, values)
+		}
+	}
+
+	return typemapper.NewCustomQueryTypeBuilder().
+		SetFieldFunc(validateField).
+		SetValidateValuesFunc(validateValues).
+		SetTypeMapperFunc(typeMap).
+		SetContextTypeMapperFunc(contextTypeMap).
+		MustBuild()
+}
@@ -0,0 +1,76 @@
+package queryparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DuplicateValues matches error of field repeated in query
+// when DuplicateReject policy is set
+var DuplicateValues = errors.New("Duplicate values")
+
+// DuplicatePolicy describe how repeated field is handled
+// 	role=user&role=admin
+type DuplicatePolicy int
+
+const (
+	// DuplicateDefault use policy of Parser for item
+	// and DuplicateAll for Parser
+	DuplicateDefault DuplicatePolicy = iota
+
+	// DuplicateAll pass all values to mapper
+	DuplicateAll
+
+	// DuplicateFirst pass only first value to mapper
+	DuplicateFirst
+
+	// DuplicateLast pass only last value to mapper
+	DuplicateLast
+
+	// DuplicateReject report DuplicateValues error if field is repeated
+	DuplicateReject
+)
+
+func (d DuplicatePolicy) String() string {
+	switch d {
+	case DuplicateDefault:
+		return "default"
+	case DuplicateAll:
+		return "all"
+	case DuplicateFirst:
+		return "first"
+	case DuplicateLast:
+		return "last"
+	case DuplicateReject:
+		return "reject"
+	}
+
+	return "unknown"
+}
+
+// duplicatePolicy return policy of item
+func (p *Parser) duplicatePolicy(item *ParseSchemaItem) DuplicatePolicy {
+	if item.DuplicatePolicy != DuplicateDefault {
+		return item.DuplicatePolicy
+	}
+
+	return p.DuplicatePolicy
+}
+
+// applyDuplicatePolicy return values that passed to mapper
+func (p *Parser) applyDuplicatePolicy(item *ParseSchemaItem, values []string) ([]string, error) {
+	if len(values) < 2 {
+		return values, nil
+	}
+
+	switch p.duplicatePolicy(item) {
+	case DuplicateFirst:
+		return values[:1], nil
+	case DuplicateLast:
+		return values[len(values)-1:], nil
+	case DuplicateReject:
+		return nil, fmt.Errorf("%w: field is repeated %d times, expect one value", DuplicateValues, len(values))
+	}
+
+	return values, nil
+}
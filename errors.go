@@ -0,0 +1,199 @@
+package queryparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+)
+
+const (
+	// UnknownFieldsKey is reserved key of ParseResult
+	// where strict Parser put UnknownFieldsError
+	UnknownFieldsKey = "$unknown"
+
+	// CanceledKey is reserved key of ParseResult
+	// where Parser put CanceledError
+	CanceledKey = "$canceled"
+)
+
+// UnknownFieldError describe query field that not found in ParseSchema
+type UnknownFieldError struct {
+	Field string
+
+	// Closest names from ParseSchema
+	Suggestions []string
+}
+
+func (u *UnknownFieldError) Error() string {
+	if len(u.Suggestions) == 0 {
+		return fmt.Sprintf("Unknown field %q", u.Field)
+	}
+
+	quoted := make([]string, len(u.Suggestions))
+	for i, s := range u.Suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+
+	return fmt.Sprintf("Unknown field %q, did you mean %s?", u.Field, strings.Join(quoted, " or "))
+}
+
+// UnknownFieldsError is collection of unknown fields of one query
+type UnknownFieldsError []*UnknownFieldError
+
+func (u UnknownFieldsError) Error() string {
+	messages := make([]string, len(u))
+	for i, err := range u {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// CanceledError returned when context of parse is done
+// before all fields are parsed
+type CanceledError struct {
+	// Error of context
+	Err error
+
+	// Fields that was not parsed
+	Skipped []string
+}
+
+func (c *CanceledError) Error() string {
+	return fmt.Sprintf("Parse canceled: %v, skipped fields: %s", c.Err, strings.Join(c.Skipped, ", "))
+}
+
+func (c *CanceledError) Unwrap() error {
+	return c.Err
+}
+
+// Is report that CanceledError matches CodeCanceled
+func (c *CanceledError) Is(target error) bool {
+	return target == CodeCanceled
+}
+
+// ErrorCode is stable machine readable code of parse error
+//
+// ErrorCode implement error, so it can be used as target of errors.Is:
+// 	errors.Is(err, queryparser.CodeInvalidValues)
+type ErrorCode string
+
+const (
+	// CodeUnknownField is code of field that not found in ParseSchema
+	CodeUnknownField ErrorCode = "unknown_field"
+
+	// CodeAmbiguousField is code of field that match many regex items
+	CodeAmbiguousField ErrorCode = "ambiguous_field"
+
+	// CodeInvalidField is code of field which format is not valid
+	CodeInvalidField ErrorCode = "invalid_field"
+
+	// CodeInvalidValues is code of field which raw values are not valid
+	CodeInvalidValues ErrorCode = "invalid_values"
+
+	// CodeDuplicateValues is code of field repeated in query
+	// when DuplicateReject policy is set
+	CodeDuplicateValues ErrorCode = "duplicate_values"
+
+	// CodeInvalidValue is code of field which values can't be mapped
+	CodeInvalidValue ErrorCode = "invalid_value"
+
+	// CodeValidationFailed is code of field which mapped value is not valid
+	CodeValidationFailed ErrorCode = "validation_failed"
+
+	// CodeCanceled is code of parse stopped by context
+	CodeCanceled ErrorCode = "canceled"
+)
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// FieldError describe error of parsing one field
+type FieldError struct {
+	// Raw field name from query
+	Field string
+
+	// Key of ParseSchema that matched field
+	Key string
+
+	Values []string
+
+	// Stage of pipeline where error happened
+	Stage Stage
+
+	Code ErrorCode
+
+	Err error
+}
+
+func newFieldError(key, field string, values []string, stage Stage, err error) *FieldError {
+	return &FieldError{
+		Field:  field,
+		Key:    key,
+		Values: values,
+		Stage:  stage,
+		Code:   errorCode(stage, err),
+		Err:    err,
+	}
+}
+
+// errorCode return code of error happened at stage
+func errorCode(stage Stage, err error) ErrorCode {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return CodeCanceled
+	case errors.Is(err, typemapper.AmbiguousField):
+		return CodeAmbiguousField
+	case errors.Is(err, DuplicateValues):
+		return CodeDuplicateValues
+	}
+
+	switch stage {
+	case StageValidateValues:
+		return CodeInvalidValues
+	case StageMap:
+		return CodeInvalidValue
+	case StageValidate:
+		return CodeValidationFailed
+	}
+
+	return CodeInvalidField
+}
+
+func (f *FieldError) Error() string {
+	return fmt.Sprintf("Field %q: %v", f.Field, f.Err)
+}
+
+func (f *FieldError) Unwrap() error {
+	return f.Err
+}
+
+// Is report that FieldError matches it Code
+func (f *FieldError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == f.Code
+}
+
+// FieldError return UnknownFieldError as FieldError
+func (u *UnknownFieldError) FieldError() *FieldError {
+	return &FieldError{
+		Field: u.Field,
+		Stage: StageValidateField,
+		Code:  CodeUnknownField,
+		Err:   u,
+	}
+}
+
+// Is report that UnknownFieldError matches CodeUnknownField
+func (u *UnknownFieldError) Is(target error) bool {
+	return target == CodeUnknownField
+}
+
+// Is report that UnknownFieldsError matches CodeUnknownField
+func (u UnknownFieldsError) Is(target error) bool {
+	return target == CodeUnknownField
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	queryparser "github.com/0B1t322/QueryParser"
+	"github.com/0B1t322/QueryParser/filter"
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+)
+
+// Code below show how to parse groups of filters with filter package
+// instead of splitting values by hand like in recursive_format example
+func NewParser() *queryparser.Parser {
+	registry := filter.NewRegistry()
+	schema := queryparser.ParseSchema{}
+
+	for _, field := range []filter.Field{
+		{Name: "name", Kind: filter.KindString, Mapper: scalar.String()},
+		{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+	} {
+		key, item := filter.Item(registry, field)
+		schema[key] = item
+	}
+
+	p := queryparser.New(typemapper.NewQueryTypeFactory(), schema)
+	return filter.AddGroups(p).MustCompile()
+}
+
+// Prints:
+//
+//	(name eq some_name and (name like dan* or name eq a,b or (age gt 3 and not age eq 5)))
+func main() {
+	u, _ := url.Parse(`http://localhost:8080?name[eq]=some_name&or=(name[like]=dan*,name[eq]="a,b",and=(age[gt]=3,not=(age[eq]=5)))`)
+
+	result := NewParser().ParseUrlValues(u.Query())
+	if err := result.Err(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(filter.FromResult(result))
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"regexp"
 	"strings"
 
 	"github.com/0B1t322/QueryParser"
@@ -32,25 +31,14 @@ func (r *Root) NewParser() *queryparser.Parser {
 	)
 }
 
-func (r *Root) getOpearationAndField(field string) (op string, cleanField string) {
-	regex := regexp.MustCompile(`(?P<field>.*)\[(?P<op>.*)\]`)
-	opAt := regex.SubexpIndex("op")
-	fieldAt := regex.SubexpIndex("field")
-	submatch := regex.FindStringSubmatch(field)
-
-	return submatch[opAt], submatch[fieldAt]
-}
-
 func (r *Root) NameParseSchema() queryparser.ParseSchemaItem {
 	return queryparser.ParseSchemaItem{
 		IsRegex: true,
-		TypeMapFunc: func(field string, values []string) (interface{}, error) {
-			op, _ := r.getOpearationAndField(field)
-
+		TypeMapCapturesFunc: func(field string, captures map[string]string, values []string) (interface{}, error) {
 			name := values[0]
 
 			r.Name = &FieldOperation{
-				Op: op,
+				Op: captures["op"],
 				Value: name,
 			}
 
@@ -86,7 +74,7 @@ func (r *Root) OrParseSchema() queryparser.ParseSchemaItem {
 
 func (r *Root) NewParseSchema() queryparser.ParseSchema {
 	return queryparser.ParseSchema{
-		`name\[.*\]`: r.NameParseSchema(),
+		`name\[(?P<op>\w+)\]`: r.NameParseSchema(),
 		"or": r.OrParseSchema(),
 	}
 }
@@ -0,0 +1,109 @@
+// Package filter provide AST of filters, registry of operators
+// and ParseSchemaItem factory that parse
+//
+//	field[op]=value
+//
+// fields to that AST
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node of filter AST: Condition, And, Or or Not
+type Node interface {
+	String() string
+
+	isNode()
+}
+
+// Condition compare field with value by operator
+type Condition struct {
+	Field string
+
+	// Name of operator in Registry
+	Operator string
+
+	// Value mapped by mapper of field, []interface{} for operators
+	// with ArityTwo and ArityMany
+	Value interface{}
+}
+
+// And is true if all nodes are true
+type And []Node
+
+// Or is true if one of nodes is true
+type Or []Node
+
+// Not negate node
+type Not struct {
+	Node Node
+}
+
+func (Condition) isNode() {}
+func (And) isNode()       {}
+func (Or) isNode()        {}
+func (Not) isNode()       {}
+
+func (c Condition) String() string {
+	return fmt.Sprintf("%s %s %v", c.Field, c.Operator, c.Value)
+}
+
+func (a And) String() string {
+	return join(a, " and ")
+}
+
+func (o Or) String() string {
+	return join(o, " or ")
+}
+
+func (n Not) String() string {
+	return "not " + n.Node.String()
+}
+
+func join(nodes []Node, separator string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.String()
+	}
+
+	return "(" + strings.Join(parts, separator) + ")"
+}
+
+// Walk call f for node and its children in depth first order,
+// children are not walked if f return false
+func Walk(node Node, f func(node Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+
+	switch node := node.(type) {
+	case And:
+		for _, child := range node {
+			Walk(child, f)
+		}
+	case Or:
+		for _, child := range node {
+			Walk(child, f)
+		}
+	case Not:
+		Walk(node.Node, f)
+	}
+}
+
+// Conditions return all conditions of node
+func Conditions(node Node) []Condition {
+	var conditions []Condition
+	Walk(
+		node,
+		func(node Node) bool {
+			if condition, ok := node.(Condition); ok {
+				conditions = append(conditions, condition)
+			}
+			return true
+		},
+	)
+
+	return conditions
+}
@@ -0,0 +1,416 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	queryparser "github.com/0B1t322/QueryParser"
+)
+
+// BadExpression matches syntax errors of expression
+var BadExpression = errors.New("Bad expression")
+
+// ExprError describe error of expression at column
+type ExprError struct {
+	// Byte offset in expression
+	Column int
+
+	Err error
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("At column %d: %v", e.Column, e.Err)
+}
+
+func (e *ExprError) Unwrap() error {
+	return e.Err
+}
+
+// symbols that can be used instead of operators
+var symbols = map[string]string{
+	"=":  Eq,
+	"!=": Ne,
+	"<":  Lt,
+	"<=": Lte,
+	">":  Gt,
+	">=": Gte,
+}
+
+// ExprItem return item of ParseSchema that parse filter expression
+//
+//	q=name eq "bob" and (age gt 18 or vip = true)
+//
+// by ParseExpr, repeated fields are joined by And
+func ExprItem(parser *queryparser.Parser) queryparser.ParseSchemaItem {
+	return queryparser.ParseSchemaItem{
+		TypeMapContextFunc: func(ctx context.Context, field string, values []string) (interface{}, error) {
+			var nodes And
+			for _, value := range values {
+				if escaped(ctx) {
+					// term of group
+					value = unescapeItem(value)
+				}
+
+				node, err := ParseExpr(ctx, parser, value)
+				if err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, node)
+			}
+
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+			return nodes, nil
+		},
+	}
+}
+
+// ParseExpr parse filter expression
+//
+//	expr       = or
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" expr ")" | comparison
+//	comparison = field operator value
+//	value      = word | string | "(" value { "," value } ")"
+//
+// where operator is name of operator or one of = != < <= > >=,
+// string is quoted by " or ' with \ escapes and list is used by in, nin and between
+//
+//	name eq "bob" and (age gt 18 or vip = true)
+//	not status in (done, canceled)
+//
+// Each comparison field op value is parsed by parser as field[op]=value,
+// so fields should be added by Item
+func ParseExpr(ctx context.Context, parser *queryparser.Parser, expr string) (Node, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &exprParser{ctx: ctx, parser: parser, tokens: tokens}
+
+	node, err := e.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if token := e.peek(); token.kind != tokenEOF {
+		return nil, e.errorf(token, "unexpected %s", token)
+	}
+
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenSymbol
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.value)
+	}
+
+	return fmt.Sprintf("%q", t.value)
+}
+
+// lex split expression to tokens
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: pos})
+			pos++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ",", pos: pos})
+			pos++
+		case r == '"' || r == '\'':
+			value, end, err := lexString(expr, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: pos})
+			pos = end
+		case strings.ContainsRune("<>=!", r):
+			end := pos + 1
+			if end < len(expr) && expr[end] == '=' {
+				end++
+			}
+
+			symbol := expr[pos:end]
+			if _, ok := symbols[symbol]; !ok {
+				return nil, &ExprError{Column: pos, Err: fmt.Errorf("%w: unknown operator %q", BadExpression, symbol)}
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, value: symbol, pos: pos})
+			pos = end
+		default:
+			end := pos
+			for end < len(expr) {
+				r, size := utf8.DecodeRuneInString(expr[end:])
+				if unicode.IsSpace(r) || strings.ContainsRune("(),\"'<>=!", r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: expr[pos:end], pos: pos})
+			pos = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+// lexString return unquoted string that start at pos and position after it
+func lexString(expr string, pos int) (string, int, error) {
+	var (
+		b     strings.Builder
+		quote = expr[pos]
+	)
+
+	for i := pos + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if i+1 == len(expr) {
+				return "", 0, &ExprError{Column: i, Err: fmt.Errorf("%w: nothing to escape", BadExpression)}
+			}
+			i++
+			b.WriteByte(expr[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(expr[i])
+		}
+	}
+
+	return "", 0, &ExprError{Column: pos, Err: fmt.Errorf("%w: unterminated string", BadExpression)}
+}
+
+// exprParser is recursive descent parser of tokens
+type exprParser struct {
+	ctx    context.Context
+	parser *queryparser.Parser
+
+	tokens []token
+	at     int
+}
+
+func (e *exprParser) errorf(t token, format string, args ...interface{}) error {
+	return &ExprError{Column: t.pos, Err: fmt.Errorf("%w: "+format, append([]interface{}{BadExpression}, args...)...)}
+}
+
+func (e *exprParser) peek() token {
+	return e.tokens[e.at]
+}
+
+func (e *exprParser) next() token {
+	t := e.tokens[e.at]
+	if t.kind != tokenEOF {
+		e.at++
+	}
+	return t
+}
+
+// keyword return true and skip token if it is word keyword
+func (e *exprParser) keyword(keyword string) bool {
+	if t := e.peek(); t.kind == tokenWord && strings.EqualFold(t.value, keyword) {
+		e.at++
+		return true
+	}
+	return false
+}
+
+func (e *exprParser) or() (Node, error) {
+	node, err := e.and()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := Or{node}
+	for e.keyword(GroupOr) {
+		if node, err = e.and(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (e *exprParser) and() (Node, error) {
+	node, err := e.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := And{node}
+	for e.keyword(GroupAnd) {
+		if node, err = e.unary(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (e *exprParser) unary() (Node, error) {
+	if e.keyword(GroupNot) {
+		node, err := e.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Node: node}, nil
+	}
+
+	if e.peek().kind == tokenLParen {
+		e.next()
+		node, err := e.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if t := e.next(); t.kind != tokenRParen {
+			return nil, e.errorf(t, "expect ) but got %s", t)
+		}
+		return node, nil
+	}
+
+	return e.comparison()
+}
+
+func (e *exprParser) comparison() (Node, error) {
+	field := e.next()
+	if field.kind != tokenWord || isKeyword(field.value) {
+		return nil, e.errorf(field, "expect field but got %s", field)
+	}
+
+	operator := e.next()
+	switch operator.kind {
+	case tokenSymbol:
+		operator.value = symbols[operator.value]
+	case tokenWord:
+	default:
+		return nil, e.errorf(operator, "expect operator but got %s", operator)
+	}
+
+	valueToken := e.peek()
+	value, err := e.values()
+	if err != nil {
+		return nil, err
+	}
+
+	return mapCondition(e.ctx, e.parser, field.value, operator.value, value, field.pos, operator.pos, valueToken.pos)
+}
+
+// mapCondition parse field[operator]=value escaped by escapeItem by parser,
+// errors are reported at position of field, operator or value,
+// CanceledError of parser is returned as is
+func mapCondition(
+	ctx context.Context,
+	parser *queryparser.Parser,
+	field, operator string,
+	value string,
+	fieldPos, operatorPos, valuePos int,
+) (Node, error) {
+	key := field + "[" + operator + "]"
+	result := parser.ParseUrlValuesContext(withEscaped(ctx), url.Values{key: {value}})
+	if canceled, find := result[queryparser.CanceledKey]; find {
+		return nil, canceled.Err
+	}
+
+	item, find := result[key]
+	if !find {
+		return nil, &ExprError{Column: fieldPos, Err: fmt.Errorf("Unknown field %q or operator %q", field, operator)}
+	} else if item.IsError() && item.Stage == queryparser.StageValidateField {
+		return nil, &ExprError{Column: operatorPos, Err: item.Err}
+	} else if item.IsError() {
+		return nil, &ExprError{Column: valuePos, Err: item.Err}
+	}
+
+	node, ok := item.Result.(Node)
+	if !ok {
+		return nil, &ExprError{Column: fieldPos, Err: fmt.Errorf("Field %q is not a filter", field)}
+	}
+
+	return node, nil
+}
+
+func isKeyword(word string) bool {
+	for _, keyword := range []string{GroupAnd, GroupOr, GroupNot} {
+		if strings.EqualFold(word, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// values return escaped value or comma joined escaped values of list,
+// so list is one value of field
+func (e *exprParser) values() (string, error) {
+	t := e.next()
+	switch t.kind {
+	case tokenWord, tokenString:
+		return escapeItem(t.value), nil
+	case tokenLParen:
+	default:
+		return "", e.errorf(t, "expect value but got %s", t)
+	}
+
+	var values []string
+	for {
+		t := e.next()
+		if t.kind != tokenWord && t.kind != tokenString {
+			return "", e.errorf(t, "expect value but got %s", t)
+		}
+		values = append(values, escapeItem(t.value))
+
+		switch t := e.next(); t.kind {
+		case tokenComma:
+		case tokenRParen:
+			return strings.Join(values, ","), nil
+		default:
+			return "", e.errorf(t, "expect , or ) but got %s", t)
+		}
+	}
+}
+
+// escapeItem escape value of list so list.ListMapper don't split it
+func escapeItem(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `,`, `\,`, `"`, `\"`).Replace(value)
+}
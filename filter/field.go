@@ -0,0 +1,254 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	queryparser "github.com/0B1t322/QueryParser"
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/list"
+)
+
+var (
+	// UnknownOperator matches OperatorError of operator not in Registry
+	UnknownOperator = errors.New("Unknown operator")
+
+	// NotAllowedOperator matches OperatorError of operator
+	// that is not allowed for field
+	NotAllowedOperator = errors.New("Operator not allowed")
+
+	// BadArity returned if count of values don't match Arity of operator
+	BadArity = errors.New("Bad count of values")
+)
+
+// OperatorError describe bad operator of field
+type OperatorError struct {
+	Field    string
+	Operator string
+
+	// Allowed operators of field
+	Allowed []string
+
+	// UnknownOperator or NotAllowedOperator
+	Err error
+}
+
+func (o *OperatorError) Error() string {
+	return fmt.Sprintf(
+		"%v %q of field %q, allowed operators: %s",
+		o.Err, o.Operator, o.Field, strings.Join(o.Allowed, ", "),
+	)
+}
+
+func (o *OperatorError) Unwrap() error {
+	return o.Err
+}
+
+// Field describe field that can be filtered
+type Field struct {
+	Name string
+
+	// Kind of field values
+	Kind Kind
+
+	// Mapper of one value of field, required,
+	// Item panic if it is not set
+	Mapper typemapper.QueryTypeMapper
+
+	// Operators allowed for field,
+	// if nil all operators of Registry that accept Kind are allowed
+	Operators []string
+
+	// DefaultOperator of field without operator, Eq if empty
+	// 	age=18
+	DefaultOperator string
+}
+
+// Allowed return operators allowed for field
+func (f Field) Allowed(registry *Registry) []string {
+	if f.Operators == nil {
+		return registry.Names(f.Kind)
+	}
+
+	allowed := append([]string(nil), f.Operators...)
+	sort.Strings(allowed)
+
+	return allowed
+}
+
+// operator return operator of field by name from query
+func (f Field) operator(registry *Registry, name string) (Operator, error) {
+	if name == "" {
+		name = f.DefaultOperator
+		if name == "" {
+			name = Eq
+		}
+	}
+
+	operator, find := registry.Lookup(name)
+	if !find {
+		return operator, &OperatorError{Field: f.Name, Operator: name, Allowed: f.Allowed(registry), Err: UnknownOperator}
+	}
+
+	allowed := operator.Accept(f.Kind)
+	if f.Operators != nil {
+		allowed = false
+		for _, op := range f.Operators {
+			allowed = allowed || op == name
+		}
+	}
+
+	if !allowed {
+		return operator, &OperatorError{Field: f.Name, Operator: name, Allowed: f.Allowed(registry), Err: NotAllowedOperator}
+	}
+
+	return operator, nil
+}
+
+// Item return regex key and item of ParseSchema
+// that map fields
+//
+//	name[op]=value
+//	name=value
+//
+// to Condition, operators are taken from registry
+//
+//	key, item := filter.Item(registry, filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()})
+//	schema[key] = item
+//
+// Values of operators with ArityTwo and ArityMany can be repeated or separated by comma.
+// Item panic if Mapper of field is not set
+func Item(registry *Registry, field Field) (string, queryparser.ParseSchemaItem) {
+	if field.Mapper == nil {
+		panic(fmt.Sprintf("filter: Mapper of field %q is not set", field.Name))
+	}
+
+	key := regexp.QuoteMeta(field.Name) + `(?:\[(?P<op>[A-Za-z_]\w*)\])?`
+
+	return key, queryparser.ParseSchemaItem{
+		IsRegex: true,
+		ValidateFieldCapturesFunc: func(_ string, captures map[string]string) error {
+			_, err := field.operator(registry, captures["op"])
+			return err
+		},
+		TypeMapContextFunc: func(ctx context.Context, _ string, values []string) (interface{}, error) {
+			operator, err := field.operator(registry, typemapper.CapturesFromContext(ctx)["op"])
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := field.mapValues(ctx, operator, values)
+			if err != nil {
+				return nil, err
+			}
+
+			return Condition{Field: field.Name, Operator: operator.Name, Value: value}, nil
+		},
+	}
+}
+
+// mapValues map values by Mapper of operator or field according to Arity of operator
+func (f Field) mapValues(ctx context.Context, operator Operator, values []string) (interface{}, error) {
+	mapper := f.Mapper
+	if operator.Mapper != nil {
+		mapper = operator.Mapper
+	}
+
+	if operator.Arity == ArityOne {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%w: operator %s take one value, got %d", BadArity, operator.Name, len(values))
+		}
+
+		if escaped(ctx) {
+			// escaped value of term is comma joined list of items
+			split, err := list.Of(mapper).Items(values)
+			if err != nil {
+				return nil, err
+			}
+
+			switch len(split) {
+			case 0:
+				values = []string{""}
+			case 1:
+				values = split
+			default:
+				return nil, fmt.Errorf("%w: operator %s take one value, got %d", BadArity, operator.Name, len(split))
+			}
+		}
+
+		if err := mapper.ValidateValues(values); err != nil {
+			return nil, err
+		}
+
+		return typemapper.MapContext(ctx, mapper, f.Name, values)
+	}
+
+	items := list.Of(mapper)
+	split, err := items.Items(values)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case operator.Arity == ArityTwo && len(split) != 2:
+		return nil, fmt.Errorf("%w: operator %s take two values, got %d", BadArity, operator.Name, len(split))
+	case len(split) == 0:
+		return nil, fmt.Errorf("%w: operator %s take one or more values", BadArity, operator.Name)
+	}
+
+	if err := items.ValidateValues(values); err != nil {
+		return nil, err
+	}
+
+	return items.MapContext(ctx, f.Name, values)
+}
+
+type escapedKey struct{}
+
+// withEscaped return context of parse which values are escaped by escapeItem,
+// so quoted values stay one item of list
+func withEscaped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, escapedKey{}, true)
+}
+
+// escaped report that values of parse are escaped
+func escaped(ctx context.Context) bool {
+	escaped, _ := ctx.Value(escapedKey{}).(bool)
+	return escaped
+}
+
+// unescapeItem return value escaped by escapeItem
+func unescapeItem(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+		}
+		b.WriteByte(value[i])
+	}
+
+	return b.String()
+}
+
+// FromResult return And of filter nodes of result sorted by field,
+// empty And if result have no nodes
+func FromResult(result queryparser.ParseResult) And {
+	fields := make([]string, 0, len(result))
+	for field, item := range result {
+		if _, ok := item.Result.(Node); ok && !item.IsError() {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	and := make(And, len(fields))
+	for i, field := range fields {
+		and[i] = result[field].Result.(Node)
+	}
+
+	return and
+}
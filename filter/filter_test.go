@@ -0,0 +1,629 @@
+package filter_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	queryparser "github.com/0B1t322/QueryParser"
+	"github.com/0B1t322/QueryParser/filter"
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/stretchr/testify/require"
+)
+
+func newParser(registry *filter.Registry, fields ...filter.Field) *queryparser.Parser {
+	schema := queryparser.ParseSchema{}
+	for _, field := range fields {
+		key, item := filter.Item(registry, field)
+		schema[key] = item
+	}
+
+	return queryparser.New(typemapper.NewQueryTypeFactory(), schema).MustCompile()
+}
+
+func TestFunc_FilterAST(t *testing.T) {
+	node := filter.And{
+		filter.Condition{Field: "age", Operator: filter.Gte, Value: 18},
+		filter.Or{
+			filter.Condition{Field: "name", Operator: filter.Like, Value: "dan*"},
+			filter.Not{Node: filter.Condition{Field: "name", Operator: filter.IsNull, Value: true}},
+		},
+	}
+
+	require.Equal(t, "(age gte 18 and (name like dan* or not name isnull true))", node.String())
+	require.Len(t, filter.Conditions(node), 3)
+
+	var visited int
+	filter.Walk(
+		node,
+		func(node filter.Node) bool {
+			visited++
+			_, isOr := node.(filter.Or)
+			return !isOr
+		},
+	)
+	require.Equal(t, 3, visited)
+}
+
+func TestFunc_FilterRegistry(t *testing.T) {
+	registry := filter.NewRegistry()
+
+	operator, find := registry.Lookup(filter.Between)
+	require.True(t, find)
+	require.Equal(t, filter.ArityTwo, operator.Arity)
+
+	require.Equal(t, []string{"eq", "in", "isnull", "ne", "nin"}, registry.Names(filter.KindBool))
+
+	registry.Register(filter.Operator{Name: "startswith", Arity: filter.ArityOne, Kinds: filter.KindString})
+	require.Contains(t, registry.Names(filter.KindString), "startswith")
+	require.NotContains(t, filter.NewRegistry().Names(filter.KindString), "startswith")
+}
+
+func TestFunc_FilterItem(t *testing.T) {
+	registry := filter.NewRegistry()
+	p := newParser(
+		registry,
+		filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+		filter.Field{Name: "name", Kind: filter.KindString, Mapper: scalar.String(), Operators: []string{filter.Eq, filter.Like}},
+		filter.Field{Name: "status", Kind: filter.KindString, Mapper: scalar.EnumStrings("new", "done"), DefaultOperator: filter.In},
+	)
+
+	t.Run(
+		"Conditions",
+		func(t *testing.T) {
+			values, err := url.ParseQuery("age[gte]=18&age[lte]=65&name[like]=dan*&status=new,done&age[between]=1,2&age[isnull]=false")
+			require.NoError(t, err)
+
+			result := p.ParseUrlValues(values)
+			require.False(t, result.HasErrors(), result.Err())
+			require.Equal(
+				t,
+				filter.And{
+					filter.Condition{Field: "age", Operator: filter.Between, Value: []interface{}{1, 2}},
+					filter.Condition{Field: "age", Operator: filter.Gte, Value: 18},
+					filter.Condition{Field: "age", Operator: filter.IsNull, Value: false},
+					filter.Condition{Field: "age", Operator: filter.Lte, Value: 65},
+					filter.Condition{Field: "name", Operator: filter.Like, Value: "dan*"},
+					filter.Condition{Field: "status", Operator: filter.In, Value: []interface{}{"new", "done"}},
+				},
+				filter.FromResult(result),
+			)
+		},
+	)
+
+	t.Run(
+		"DefaultOperator",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"age": {"18"}})
+			require.Equal(t, filter.Condition{Field: "age", Operator: filter.Eq, Value: 18}, result["age"].Result)
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{
+				"age[foo]":     {"1"},
+				"age[like]":    {"1"},
+				"name[gt]":     {"a"},
+				"age[between]": {"1,2,3"},
+				"age[eq]":      {"1", "2"},
+				"age[in]":      {""},
+				"age[lt]":      {"x"},
+			})
+
+			require.ErrorIs(t, result["age[foo]"].Err, filter.UnknownOperator)
+			require.Equal(t, queryparser.StageValidateField, result["age[foo]"].Stage)
+			require.ErrorIs(t, result["age[like]"].Err, filter.NotAllowedOperator)
+			require.ErrorIs(t, result["name[gt]"].Err, filter.NotAllowedOperator)
+			require.Contains(t, result["name[gt]"].Err.Error(), "allowed operators: eq, like")
+			require.ErrorIs(t, result["age[between]"].Err, filter.BadArity)
+			require.ErrorIs(t, result["age[eq]"].Err, filter.BadArity)
+			require.ErrorIs(t, result["age[in]"].Err, filter.BadArity)
+			require.ErrorIs(t, result["age[lt]"].Err, scalar.BadSyntax)
+			require.Empty(t, filter.FromResult(result))
+		},
+	)
+
+	t.Run(
+		"NoMapper",
+		func(t *testing.T) {
+			require.PanicsWithValue(
+				t,
+				`filter: Mapper of field "age" is not set`,
+				func() { filter.Item(registry, filter.Field{Name: "age", Kind: filter.KindNumber}) },
+			)
+		},
+	)
+}
+
+func TestFunc_FilterGroup(t *testing.T) {
+	p := newParser(
+		filter.NewRegistry(),
+		filter.Field{Name: "name", Kind: filter.KindString, Mapper: scalar.String()},
+		filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+	)
+	p.ParseSchema["q"] = filter.ExprItem(p)
+	filter.AddGroups(p).MustCompile()
+
+	parse := func(t *testing.T, query string) queryparser.ParseResult {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		return p.ParseUrlValues(values)
+	}
+
+	t.Run(
+		"Nested",
+		func(t *testing.T) {
+			result := parse(t, "or=(name[eq]=a,and=(age[gt]=3,age[lt]=9,not=(age[eq]=5)))")
+			require.False(t, result.HasErrors(), result.Err())
+			require.Equal(
+				t,
+				filter.Or{
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: "a"},
+					filter.And{
+						filter.Condition{Field: "age", Operator: filter.Gt, Value: 3},
+						filter.Condition{Field: "age", Operator: filter.Lt, Value: 9},
+						filter.Not{Node: filter.Condition{Field: "age", Operator: filter.Eq, Value: 5}},
+					},
+				},
+				result["or"].Result,
+			)
+		},
+	)
+
+	t.Run(
+		"WithFields",
+		func(t *testing.T) {
+			result := parse(t, "name[like]=dan*&not=(age[lt]=18,age[gt]=65)")
+			require.Equal(
+				t,
+				"(name like dan* and not (age lt 18 and age gt 65))",
+				filter.FromResult(result).String(),
+			)
+		},
+	)
+
+	t.Run(
+		"QuoteAndEscape",
+		func(t *testing.T) {
+			result := parse(t, `or=(name[eq]="a,b=(c)",name[eq]=d\,e\)f,name[eq]="say \"hi\"",age[in]=(1,"2"))`)
+			require.False(t, result.HasErrors(), result.Err())
+			require.Equal(
+				t,
+				filter.Or{
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: "a,b=(c)"},
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: "d,e)f"},
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: `say "hi"`},
+					filter.Condition{Field: "age", Operator: filter.In, Value: []interface{}{1, 2}},
+				},
+				result["or"].Result,
+			)
+
+			result = parse(t, `or=(name[in]="a,b",name[in]=a\,b,name[in]=("a,b",c\\))`)
+			require.False(t, result.HasErrors(), result.Err())
+			require.Equal(
+				t,
+				filter.Or{
+					filter.Condition{Field: "name", Operator: filter.In, Value: []interface{}{"a,b"}},
+					filter.Condition{Field: "name", Operator: filter.In, Value: []interface{}{"a,b"}},
+					filter.Condition{Field: "name", Operator: filter.In, Value: []interface{}{"a,b", `c\`}},
+				},
+				result["or"].Result,
+			)
+		},
+	)
+
+	t.Run(
+		"Expr",
+		func(t *testing.T) {
+			result := parse(t, `or=(q="name in (\"a,b\", c\\d) and age gt 1",age[lt]=0)`)
+			require.False(t, result.HasErrors(), result.Err())
+			require.Equal(t, `((name in [a,b c\d] and age gt 1) or age lt 0)`, result["or"].Result.(filter.Node).String())
+		},
+	)
+
+	t.Run(
+		"DuplicatePolicy",
+		func(t *testing.T) {
+			// list is one value of field, so policy of repeated values don't apply
+			for _, policy := range []queryparser.DuplicatePolicy{queryparser.DuplicateFirst, queryparser.DuplicateReject} {
+				p := newParser(
+					filter.NewRegistry(),
+					filter.Field{Name: "name", Kind: filter.KindString, Mapper: scalar.String()},
+					filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+				)
+				p.DuplicatePolicy = policy
+				filter.AddGroups(p).MustCompile()
+
+				result := p.ParseUrlValues(url.Values{"or": {`(age[in]=(1,2,3),name[eq]=a)`}})
+				require.False(t, result.HasErrors(), result.Err())
+				require.Equal(
+					t,
+					filter.Or{
+						filter.Condition{Field: "age", Operator: filter.In, Value: []interface{}{1, 2, 3}},
+						filter.Condition{Field: "name", Operator: filter.Eq, Value: "a"},
+					},
+					result["or"].Result,
+				)
+			}
+
+			result := parse(t, "or=(name[eq]=(a,b))")
+			require.ErrorIs(t, result["or"].Err, filter.BadArity)
+		},
+	)
+
+	t.Run(
+		"Canceled",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			// term is parsed by parser with context of group
+			_, err := filter.GroupItem(p, filter.GroupOr).TypeMapContextFunc(ctx, "or", []string{"name[eq]=a"})
+			require.ErrorIs(t, err, queryparser.CodeCanceled)
+			require.ErrorIs(t, err, context.Canceled)
+		},
+	)
+
+	t.Run(
+		"SingleTerm",
+		func(t *testing.T) {
+			result := parse(t, "not=name[eq]=a")
+			require.Equal(t, filter.Not{Node: filter.Condition{Field: "name", Operator: filter.Eq, Value: "a"}}, result["not"].Result)
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			for query, pos := range map[string]int{
+				"or=(name[eq]=a":            11,
+				"or=(name[eq]=a)x":          12,
+				"or=(name[eq]=a))":          12,
+				"or=(name[eq]=a,nope)":      16,
+				`or=(name[eq]="a)`:          13,
+				"or=(name[eq]=f(x))":        11,
+				"or=(and=name[eq]=a)":       5,
+				"or=(age[gt]=x)":            9,
+				"or=(height[gt]=1)":         1,
+				"or=(name[eq]=a,and=(age))": 20,
+				"or=(age[in]=(1,2)":         14,
+				`or=(age[in]=(1"2))`:        11,
+			} {
+				result := parse(t, query)
+
+				var groupErr *filter.GroupError
+				require.ErrorAs(t, result["or"].Err, &groupErr, query)
+				require.Equal(t, pos, groupErr.Pos, "%s: %v", query, groupErr)
+			}
+
+			result := parse(t, "or=(age[gt]=x)")
+			require.ErrorIs(t, result["or"].Err, scalar.BadSyntax)
+
+			result = parse(t, "or=(name[eq]=a")
+			require.ErrorIs(t, result["or"].Err, filter.BadGroup)
+			require.EqualError(t, result["or"].Err, `Field "or": At position 11: Bad group syntax: expect )`)
+		},
+	)
+}
+
+func TestFunc_FilterExpr(t *testing.T) {
+	p := newParser(
+		filter.NewRegistry(),
+		filter.Field{Name: "name", Kind: filter.KindString, Mapper: scalar.String()},
+		filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+		filter.Field{Name: "vip", Kind: filter.KindBool, Mapper: scalar.Bool()},
+	)
+	p.ParseSchema["q"] = filter.ExprItem(p)
+	p.MustCompile()
+
+	parse := func(expr string) (filter.Node, error) {
+		return filter.ParseExpr(context.Background(), p, expr)
+	}
+
+	t.Run(
+		"Precedence",
+		func(t *testing.T) {
+			node, err := parse(`name eq "bob" and (age gt 18 or vip = true)`)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				filter.And{
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: "bob"},
+					filter.Or{
+						filter.Condition{Field: "age", Operator: filter.Gt, Value: 18},
+						filter.Condition{Field: "vip", Operator: filter.Eq, Value: true},
+					},
+				},
+				node,
+			)
+
+			node, err = parse(`age >= 18 and age < 65 or NOT vip = false and name like 'dan*'`)
+			require.NoError(t, err)
+			require.Equal(t, "((age gte 18 and age lt 65) or (not vip eq false and name like dan*))", node.String())
+		},
+	)
+
+	t.Run(
+		"Lists",
+		func(t *testing.T) {
+			node, err := parse(`not name in ("a,b", 'c"d', e\f) and age between (18, 65)`)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				filter.And{
+					filter.Not{Node: filter.Condition{Field: "name", Operator: filter.In, Value: []interface{}{"a,b", `c"d`, `e\f`}}},
+					filter.Condition{Field: "age", Operator: filter.Between, Value: []interface{}{18, 65}},
+				},
+				node,
+			)
+
+			node, err = parse(`name in "a,b" or name in 'c\,d' or name eq "a,b" or name eq e\f`)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				filter.Or{
+					filter.Condition{Field: "name", Operator: filter.In, Value: []interface{}{"a,b"}},
+					filter.Condition{Field: "name", Operator: filter.In, Value: []interface{}{"c,d"}},
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: "a,b"},
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: `e\f`},
+				},
+				node,
+			)
+		},
+	)
+
+	t.Run(
+		"DuplicatePolicy",
+		func(t *testing.T) {
+			// list is one value of field, so policy of repeated values don't apply
+			for _, policy := range []queryparser.DuplicatePolicy{queryparser.DuplicateFirst, queryparser.DuplicateReject} {
+				p := newParser(
+					filter.NewRegistry(),
+					filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+				)
+				p.DuplicatePolicy = policy
+				p.MustCompile()
+
+				node, err := filter.ParseExpr(context.Background(), p, "age in (1, 2, 3) and age between (1, 9)")
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					filter.And{
+						filter.Condition{Field: "age", Operator: filter.In, Value: []interface{}{1, 2, 3}},
+						filter.Condition{Field: "age", Operator: filter.Between, Value: []interface{}{1, 9}},
+					},
+					node,
+				)
+			}
+
+			_, err := parse("name eq (a, b)")
+			require.ErrorIs(t, err, filter.BadArity)
+		},
+	)
+
+	t.Run(
+		"Canceled",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := filter.ParseExpr(ctx, p, "name eq a")
+			require.ErrorIs(t, err, queryparser.CodeCanceled)
+			require.ErrorIs(t, err, context.Canceled)
+			require.IsType(t, &queryparser.CanceledError{}, err)
+		},
+	)
+
+	t.Run(
+		"Item",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"q": {"age gt 18"}, "name[eq]": {"bob"}})
+			require.False(t, result.HasErrors(), result.Err())
+			require.Equal(t, "(name eq bob and age gt 18)", filter.FromResult(result).String())
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			for expr, column := range map[string]int{
+				`name eq "bob`:            8,
+				`name eq bob and`:         15,
+				`name eq bob)`:            11,
+				`(name eq bob`:            12,
+				`name bob`:                8,
+				`name =< bob`:             6,
+				`name eq`:                 7,
+				`name in (a, b`:           13,
+				`name in (a b)`:           11,
+				`age eq x`:                7,
+				`age like x`:              4,
+				`height gt 1`:             0,
+				`age gt 1 or or age lt 2`: 12,
+				`name eq "a\`:             10,
+			} {
+				_, err := parse(expr)
+
+				var exprErr *filter.ExprError
+				require.ErrorAs(t, err, &exprErr, expr)
+				require.Equal(t, column, exprErr.Column, "%s: %v", expr, err)
+			}
+
+			_, err := parse(`age eq x`)
+			require.ErrorIs(t, err, scalar.BadSyntax)
+
+			_, err = parse(`(name eq bob`)
+			require.ErrorIs(t, err, filter.BadExpression)
+			require.EqualError(t, err, "At column 12: Bad expression: expect ) but got end of expression")
+		},
+	)
+}
+
+func TestFunc_FilterRSQL(t *testing.T) {
+	registry := filter.NewRegistry()
+	registry.Register(filter.Operator{Name: "startswith", Arity: filter.ArityOne, Kinds: filter.KindString})
+
+	p := newParser(
+		registry,
+		filter.Field{Name: "name", Kind: filter.KindString, Mapper: scalar.String()},
+		filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+		filter.Field{Name: "vip", Kind: filter.KindBool, Mapper: scalar.Bool()},
+	)
+	p.ParseSchema["filter"] = filter.RSQLItem(p)
+	p.MustCompile()
+
+	parse := func(input string) (filter.Node, error) {
+		return filter.ParseRSQL(context.Background(), p, input)
+	}
+
+	t.Run(
+		"Precedence",
+		func(t *testing.T) {
+			node, err := parse("name==bob;age=gt=18,vip==true")
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				filter.Or{
+					filter.And{
+						filter.Condition{Field: "name", Operator: filter.Eq, Value: "bob"},
+						filter.Condition{Field: "age", Operator: filter.Gt, Value: 18},
+					},
+					filter.Condition{Field: "vip", Operator: filter.Eq, Value: true},
+				},
+				node,
+			)
+
+			node, err = parse("name!=bob;(age<18 or age>=65) and vip==false")
+			require.NoError(t, err)
+			require.Equal(t, "(name ne bob and (age lt 18 or age gte 65) and vip eq false)", node.String())
+		},
+	)
+
+	t.Run(
+		"Comparators",
+		func(t *testing.T) {
+			node, err := parse(`name=out=("a,b",'c"d',e);age=between=(18, 65);name=startswith=dan;age=le=9;age=ge=1`)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				filter.And{
+					filter.Condition{Field: "name", Operator: filter.Nin, Value: []interface{}{"a,b", `c"d`, "e"}},
+					filter.Condition{Field: "age", Operator: filter.Between, Value: []interface{}{18, 65}},
+					filter.Condition{Field: "name", Operator: "startswith", Value: "dan"},
+					filter.Condition{Field: "age", Operator: filter.Lte, Value: 9},
+					filter.Condition{Field: "age", Operator: filter.Gte, Value: 1},
+				},
+				node,
+			)
+
+			node, err = parse(`name=in="a,b",name=out='c\,d',name=="a,b",name==e\f`)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				filter.Or{
+					filter.Condition{Field: "name", Operator: filter.In, Value: []interface{}{"a,b"}},
+					filter.Condition{Field: "name", Operator: filter.Nin, Value: []interface{}{"c,d"}},
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: "a,b"},
+					filter.Condition{Field: "name", Operator: filter.Eq, Value: `e\f`},
+				},
+				node,
+			)
+		},
+	)
+
+	t.Run(
+		"DuplicatePolicy",
+		func(t *testing.T) {
+			// list is one value of selector, so policy of repeated values don't apply
+			for _, policy := range []queryparser.DuplicatePolicy{queryparser.DuplicateFirst, queryparser.DuplicateReject} {
+				p := newParser(
+					filter.NewRegistry(),
+					filter.Field{Name: "age", Kind: filter.KindNumber, Mapper: scalar.Int()},
+				)
+				p.DuplicatePolicy = policy
+				p.MustCompile()
+
+				node, err := filter.ParseRSQL(context.Background(), p, "age=in=(1,2,3);age=between=(1,9)")
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					filter.And{
+						filter.Condition{Field: "age", Operator: filter.In, Value: []interface{}{1, 2, 3}},
+						filter.Condition{Field: "age", Operator: filter.Between, Value: []interface{}{1, 9}},
+					},
+					node,
+				)
+			}
+
+			_, err := parse("name==(a,b)")
+			require.ErrorIs(t, err, filter.BadArity)
+		},
+	)
+
+	t.Run(
+		"Canceled",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := filter.ParseRSQL(ctx, p, "name==a")
+			require.ErrorIs(t, err, queryparser.CodeCanceled)
+			require.IsType(t, &queryparser.CanceledError{}, err)
+		},
+	)
+
+	t.Run(
+		"Item",
+		func(t *testing.T) {
+			values, err := url.ParseQuery("filter=age=in=(1,2)%3Bvip==true&name[eq]=bob")
+			require.NoError(t, err)
+
+			result := p.ParseUrlValues(values)
+			require.False(t, result.HasErrors(), result.Err())
+			require.Equal(t, "((age in [1 2] and vip eq true) and name eq bob)", filter.FromResult(result).String())
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			for input, column := range map[string]int{
+				`name=="bob`:         6,
+				`name==bob;`:         10,
+				`name==bob)`:         9,
+				`(name==bob`:         10,
+				`name=bob`:           4,
+				`name=i n=bob`:       4,
+				`name~=bob`:          4,
+				`name==`:             6,
+				`name=in=(a,b`:       12,
+				`name=in=(a b)`:      11,
+				`age==x`:             5,
+				`age=like=x`:         3,
+				`age=foo=x`:          3,
+				`height=gt=1`:        0,
+				`age=gt=1,,age=lt=2`: 9,
+				`name=="a\`:          8,
+			} {
+				_, err := parse(input)
+
+				var exprErr *filter.ExprError
+				require.ErrorAs(t, err, &exprErr, input)
+				require.Equal(t, column, exprErr.Column, "%s: %v", input, err)
+			}
+
+			_, err := parse("age=like=x")
+			require.ErrorIs(t, err, filter.NotAllowedOperator)
+
+			_, err = parse("age==x")
+			require.ErrorIs(t, err, scalar.BadSyntax)
+
+			_, err = parse("(name==bob")
+			require.ErrorIs(t, err, filter.BadExpression)
+			require.EqualError(t, err, "At column 10: Bad expression: expect ) but got end of expression")
+		},
+	)
+}
@@ -0,0 +1,285 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	queryparser "github.com/0B1t322/QueryParser"
+)
+
+// Names of group fields
+const (
+	GroupAnd = "and"
+	GroupOr  = "or"
+	GroupNot = "not"
+)
+
+// BadGroup matches syntax errors of group
+var BadGroup = errors.New("Bad group syntax")
+
+// GroupError describe error of group value at position
+type GroupError struct {
+	// Byte offset in value
+	Pos int
+
+	Err error
+}
+
+func (g *GroupError) Error() string {
+	return fmt.Sprintf("At position %d: %v", g.Pos, g.Err)
+}
+
+func (g *GroupError) Unwrap() error {
+	return g.Err
+}
+
+// GroupItem return item of ParseSchema that parse group of terms to Or, And or Not
+// by name of group
+//
+//	or=(name[eq]=a,and=(age[gt]=3,age[lt]=9))
+//	not=(status=done)
+//
+// Terms of group are separated by comma, each term is
+// nested group or field that parsed by parser, so groups can be nested
+// without limit. Values that contain comma, parenthesis or quote
+// should be quoted "a,b" or escaped a\,b, in quoted value quote is escaped \".
+// Values of list operators are in parenthesis
+//
+//	or=(age[in]=(1,2),name[in]=("a,b",c))
+//
+// Repeated group fields are joined by And
+func GroupItem(parser *queryparser.Parser, group string) queryparser.ParseSchemaItem {
+	return queryparser.ParseSchemaItem{
+		TypeMapContextFunc: func(ctx context.Context, field string, values []string) (interface{}, error) {
+			var nodes And
+			for _, value := range values {
+				g := &groupParser{ctx: ctx, parser: parser, input: value}
+				node, err := g.parse(group)
+				if err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, node)
+			}
+
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+			return nodes, nil
+		},
+	}
+}
+
+// AddGroups add and, or and not GroupItem to ParseSchema of parser,
+// it should be called before Compile
+func AddGroups(parser *queryparser.Parser) *queryparser.Parser {
+	for _, group := range []string{GroupAnd, GroupOr, GroupNot} {
+		parser.ParseSchema[group] = GroupItem(parser, group)
+	}
+
+	return parser
+}
+
+// groupParser is recursive descent parser of group value
+type groupParser struct {
+	ctx    context.Context
+	parser *queryparser.Parser
+
+	input string
+	pos   int
+}
+
+func (g *groupParser) errorf(format string, args ...interface{}) error {
+	return &GroupError{Pos: g.pos, Err: fmt.Errorf("%w: "+format, append([]interface{}{BadGroup}, args...)...)}
+}
+
+func (g *groupParser) peek() byte {
+	if g.pos < len(g.input) {
+		return g.input[g.pos]
+	}
+	return 0
+}
+
+// parse parse whole input as group
+func (g *groupParser) parse(group string) (Node, error) {
+	node, err := g.group(group)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.pos < len(g.input) {
+		return nil, g.errorf("unexpected %q", g.peek())
+	}
+
+	return node, nil
+}
+
+// group parse terms in parenthesis or single term
+func (g *groupParser) group(group string) (Node, error) {
+	var terms []Node
+	if g.peek() == '(' {
+		g.pos++
+
+		for closed := false; !closed; {
+			term, err := g.term()
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, term)
+
+			switch g.peek() {
+			case ',':
+				g.pos++
+			case ')':
+				g.pos++
+				closed = true
+			case 0:
+				return nil, g.errorf("expect )")
+			default:
+				return nil, g.errorf("expect , or ) but got %q", g.peek())
+			}
+		}
+	} else {
+		term, err := g.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	switch group {
+	case GroupOr:
+		return Or(terms), nil
+	case GroupNot:
+		if len(terms) == 1 {
+			return Not{Node: terms[0]}, nil
+		}
+		return Not{Node: And(terms)}, nil
+	}
+
+	return And(terms), nil
+}
+
+// term parse field=value or nested group
+func (g *groupParser) term() (Node, error) {
+	start := g.pos
+	at := strings.IndexAny(g.input[g.pos:], "=,()\"")
+	if at <= 0 || g.input[g.pos+at] != '=' {
+		if at > 0 {
+			g.pos += at
+		}
+		return nil, g.errorf("expect field=value")
+	}
+
+	field := g.input[g.pos : g.pos+at]
+	g.pos += at + 1
+
+	switch field {
+	case GroupAnd, GroupOr, GroupNot:
+		if g.peek() != '(' {
+			return nil, g.errorf("expect ( after %s=", field)
+		}
+		return g.group(field)
+	}
+
+	valueStart := g.pos
+	value, err := g.values()
+	if err != nil {
+		return nil, err
+	}
+
+	result := g.parser.ParseUrlValuesContext(withEscaped(g.ctx), url.Values{field: {value}})
+	if canceled, find := result[queryparser.CanceledKey]; find {
+		return nil, canceled.Err
+	}
+
+	item, find := result[field]
+	if !find {
+		return nil, &GroupError{Pos: start, Err: fmt.Errorf("Unknown field %q", field)}
+	} else if item.IsError() {
+		return nil, &GroupError{Pos: valueStart, Err: item.Err}
+	}
+
+	node, ok := item.Result.(Node)
+	if !ok {
+		return nil, &GroupError{Pos: start, Err: fmt.Errorf("Field %q is not a filter", field)}
+	}
+
+	return node, nil
+}
+
+// values return escaped value or comma joined escaped values of list in parenthesis,
+// so list is one value of field
+func (g *groupParser) values() (string, error) {
+	if g.peek() != '(' {
+		value, err := g.value()
+		if err != nil {
+			return "", err
+		}
+		return escapeItem(value), nil
+	}
+	g.pos++
+
+	var values []string
+	for {
+		value, err := g.value()
+		if err != nil {
+			return "", err
+		}
+		values = append(values, escapeItem(value))
+
+		switch g.peek() {
+		case ',':
+			g.pos++
+		case ')':
+			g.pos++
+			return strings.Join(values, ","), nil
+		case 0:
+			return "", g.errorf("expect )")
+		default:
+			return "", g.errorf("expect , or ) but got %q", g.peek())
+		}
+	}
+}
+
+// value parse quoted or raw value until comma or parenthesis
+func (g *groupParser) value() (string, error) {
+	var (
+		b      strings.Builder
+		quoted = g.peek() == '"'
+	)
+	if quoted {
+		g.pos++
+	}
+
+	for g.pos < len(g.input) {
+		c := g.input[g.pos]
+		switch {
+		case c == '\\':
+			if g.pos+1 == len(g.input) {
+				return "", g.errorf("nothing to escape")
+			}
+			b.WriteByte(g.input[g.pos+1])
+			g.pos += 2
+			continue
+		case quoted && c == '"':
+			g.pos++
+			return b.String(), nil
+		case !quoted && (c == ',' || c == ')'):
+			return b.String(), nil
+		case !quoted && (c == '(' || c == '"'):
+			return "", g.errorf("unexpected %q in value, quote or escape it", c)
+		}
+
+		b.WriteByte(c)
+		g.pos++
+	}
+
+	if quoted {
+		return "", g.errorf("unterminated quote")
+	}
+
+	return b.String(), nil
+}
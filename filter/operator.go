@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+)
+
+// Arity is count of values that operator take
+type Arity int
+
+const (
+	// ArityOne operator take one value: age[eq]=18
+	ArityOne Arity = iota
+
+	// ArityTwo operator take two values: age[between]=18,65
+	ArityTwo
+
+	// ArityMany operator take one or more values: id[in]=1,2,3
+	ArityMany
+)
+
+func (a Arity) String() string {
+	switch a {
+	case ArityOne:
+		return "one"
+	case ArityTwo:
+		return "two"
+	case ArityMany:
+		return "many"
+	}
+
+	return "unknown"
+}
+
+// Kind is set of value types
+type Kind int
+
+const (
+	KindString Kind = 1 << iota
+	KindNumber
+	KindTime
+	KindBool
+
+	// KindOrdered is kinds that can be compared
+	KindOrdered = KindString | KindNumber | KindTime
+
+	// KindAny is all kinds
+	KindAny = KindOrdered | KindBool
+)
+
+// Operator describe which values operator take
+type Operator struct {
+	Name string
+
+	Arity Arity
+
+	// Kinds of fields that operator accept
+	Kinds Kind
+
+	// Mapper of values used instead of mapper of field if set,
+	// for example isnull take bool
+	Mapper typemapper.QueryTypeMapper
+}
+
+// Accept return true if operator can be used with field of kind
+func (o *Operator) Accept(kind Kind) bool {
+	return o.Kinds&kind != 0
+}
+
+// Names of builtin operators
+const (
+	Eq       = "eq"
+	Ne       = "ne"
+	Lt       = "lt"
+	Lte      = "lte"
+	Gt       = "gt"
+	Gte      = "gte"
+	In       = "in"
+	Nin      = "nin"
+	Like     = "like"
+	ILike    = "ilike"
+	IsNull   = "isnull"
+	Between  = "between"
+	Contains = "contains"
+)
+
+// Builtin return builtin operators
+func Builtin() []Operator {
+	return []Operator{
+		{Name: Eq, Arity: ArityOne, Kinds: KindAny},
+		{Name: Ne, Arity: ArityOne, Kinds: KindAny},
+		{Name: Lt, Arity: ArityOne, Kinds: KindOrdered},
+		{Name: Lte, Arity: ArityOne, Kinds: KindOrdered},
+		{Name: Gt, Arity: ArityOne, Kinds: KindOrdered},
+		{Name: Gte, Arity: ArityOne, Kinds: KindOrdered},
+		{Name: In, Arity: ArityMany, Kinds: KindAny},
+		{Name: Nin, Arity: ArityMany, Kinds: KindAny},
+		{Name: Like, Arity: ArityOne, Kinds: KindString},
+		{Name: ILike, Arity: ArityOne, Kinds: KindString},
+		{Name: IsNull, Arity: ArityOne, Kinds: KindAny, Mapper: scalar.Bool()},
+		{Name: Between, Arity: ArityTwo, Kinds: KindOrdered},
+		{Name: Contains, Arity: ArityOne, Kinds: KindString},
+	}
+}
+
+// Registry of operators, it safe for concurrent use
+type Registry struct {
+	operators map[string]Operator
+
+	mu sync.RWMutex
+}
+
+// NewRegistry return registry with builtin operators
+func NewRegistry() *Registry {
+	registry := &Registry{operators: map[string]Operator{}}
+	for _, operator := range Builtin() {
+		registry.Register(operator)
+	}
+
+	return registry
+}
+
+// Register add operator or replace operator with same name
+func (r *Registry) Register(operator Operator) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.operators[operator.Name] = operator
+	return r
+}
+
+// Lookup return operator by name
+func (r *Registry) Lookup(name string) (Operator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	operator, find := r.operators[name]
+	return operator, find
+}
+
+// Names return sorted names of operators that accept kind
+func (r *Registry) Names(kind Kind) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []string
+	for name, operator := range r.operators {
+		if operator.Accept(kind) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
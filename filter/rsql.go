@@ -0,0 +1,349 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	queryparser "github.com/0B1t322/QueryParser"
+)
+
+// rsqlComparators map RSQL/FIQL comparators to operators,
+// other =name= comparators are passed as operator name
+var rsqlComparators = map[string]string{
+	"==":    Eq,
+	"!=":    Ne,
+	"=lt=":  Lt,
+	"=le=":  Lte,
+	"=gt=":  Gt,
+	"=ge=":  Gte,
+	"=in=":  In,
+	"=out=": Nin,
+	"<":     Lt,
+	"<=":    Lte,
+	">":     Gt,
+	">=":    Gte,
+}
+
+// rsqlReserved can't be used in selector and unquoted argument
+const rsqlReserved = "\"'();,=!~<> \t\r\n"
+
+// RSQLItem return item of ParseSchema that parse RSQL/FIQL filter
+//
+//	filter=name==bob;age=gt=18,vip==true
+//
+// by ParseRSQL, repeated fields are joined by And
+func RSQLItem(parser *queryparser.Parser) queryparser.ParseSchemaItem {
+	return queryparser.ParseSchemaItem{
+		TypeMapContextFunc: func(ctx context.Context, field string, values []string) (interface{}, error) {
+			var nodes And
+			for _, value := range values {
+				if escaped(ctx) {
+					// term of group
+					value = unescapeItem(value)
+				}
+
+				node, err := ParseRSQL(ctx, parser, value)
+				if err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, node)
+			}
+
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+			return nodes, nil
+		},
+	}
+}
+
+// ParseRSQL parse RSQL/FIQL filter
+//
+//	or         = and { "," and }
+//	and        = constraint { ";" constraint }
+//	constraint = "(" or ")" | comparison
+//	comparison = selector comparator arguments
+//	arguments  = "(" value { "," value } ")" | value
+//
+// where ";" is and, "," is or and ";" bind tighter than ",",
+// they also can be written as " and " and " or ".
+// Comparator is == != < <= > >= or =name=, =lt= =le= =gt= =ge= =in= =out=
+// are mapped to lt, lte, gt, gte, in and nin, other =name= to name,
+// so own operators of Registry can be used. Value is quoted by " or ' with \ escapes
+//
+//	name==bob;age=gt=18,vip==true
+//	status=out=(done,canceled);(age=lt=18,age=between=(60,65))
+//
+// Each comparison selector comparator value is parsed by parser as selector[op]=value,
+// so fields should be added by Item, syntax errors are *ExprError
+func ParseRSQL(ctx context.Context, parser *queryparser.Parser, input string) (Node, error) {
+	r := &rsqlParser{ctx: ctx, parser: parser, input: input}
+
+	node, err := r.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.skipSpace(); r.pos < len(r.input) {
+		return nil, r.errorf("unexpected %s", r.got())
+	}
+
+	return node, nil
+}
+
+// rsqlParser is recursive descent parser of RSQL
+type rsqlParser struct {
+	ctx    context.Context
+	parser *queryparser.Parser
+
+	input string
+	pos   int
+}
+
+func (r *rsqlParser) errorf(format string, args ...interface{}) error {
+	return &ExprError{Column: r.pos, Err: fmt.Errorf("%w: "+format, append([]interface{}{BadExpression}, args...)...)}
+}
+
+func (r *rsqlParser) peek() byte {
+	if r.pos < len(r.input) {
+		return r.input[r.pos]
+	}
+	return 0
+}
+
+// got describe input at position for errors
+func (r *rsqlParser) got() string {
+	if r.pos == len(r.input) {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", r.peek())
+}
+
+// skipSpace skip spaces and return true if any was skipped
+func (r *rsqlParser) skipSpace() bool {
+	start := r.pos
+	for r.pos < len(r.input) && strings.IndexByte(" \t\r\n", r.input[r.pos]) >= 0 {
+		r.pos++
+	}
+	return r.pos > start
+}
+
+// logical return true and skip logical operator written as symbol or keyword
+func (r *rsqlParser) logical(symbol byte, keyword string) bool {
+	start := r.pos
+	spaced := r.skipSpace()
+
+	if r.peek() == symbol {
+		r.pos++
+		return true
+	}
+
+	end := r.pos + len(keyword)
+	if spaced && end < len(r.input) && strings.EqualFold(r.input[r.pos:end], keyword) &&
+		strings.IndexByte(" \t\r\n", r.input[end]) >= 0 {
+		r.pos = end
+		return true
+	}
+
+	r.pos = start
+	return false
+}
+
+func (r *rsqlParser) or() (Node, error) {
+	node, err := r.and()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := Or{node}
+	for r.logical(',', GroupOr) {
+		if node, err = r.and(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (r *rsqlParser) and() (Node, error) {
+	node, err := r.constraint()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := And{node}
+	for r.logical(';', GroupAnd) {
+		if node, err = r.constraint(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (r *rsqlParser) constraint() (Node, error) {
+	r.skipSpace()
+	if r.peek() != '(' {
+		return r.comparison()
+	}
+
+	r.pos++
+	node, err := r.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.skipSpace(); r.peek() != ')' {
+		return nil, r.errorf("expect ) but got %s", r.got())
+	}
+	r.pos++
+
+	return node, nil
+}
+
+func (r *rsqlParser) comparison() (Node, error) {
+	fieldPos := r.pos
+	field := r.word()
+	if field == "" {
+		return nil, r.errorf("expect selector but got %s", r.got())
+	}
+
+	r.skipSpace()
+	operatorPos := r.pos
+	operator, err := r.comparator()
+	if err != nil {
+		return nil, err
+	}
+
+	r.skipSpace()
+	valuePos := r.pos
+	value, err := r.arguments()
+	if err != nil {
+		return nil, err
+	}
+
+	return mapCondition(r.ctx, r.parser, field, operator, value, fieldPos, operatorPos, valuePos)
+}
+
+// word return unreserved string at position
+func (r *rsqlParser) word() string {
+	start := r.pos
+	for r.pos < len(r.input) && strings.IndexByte(rsqlReserved, r.input[r.pos]) < 0 {
+		r.pos++
+	}
+	return r.input[start:r.pos]
+}
+
+// comparator return operator of comparator at position
+func (r *rsqlParser) comparator() (string, error) {
+	rest := r.input[r.pos:]
+	for _, symbol := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, symbol) {
+			r.pos += len(symbol)
+			return rsqlComparators[symbol], nil
+		}
+	}
+
+	if !strings.HasPrefix(rest, "=") {
+		return "", r.errorf("expect comparator but got %s", r.got())
+	}
+
+	end := strings.IndexByte(rest[1:], '=') + 1
+	if end <= 1 || !isOperatorName(rest[1:end]) {
+		return "", r.errorf("expect comparator like =name=")
+	}
+
+	comparator := rest[:end+1]
+	r.pos += len(comparator)
+
+	if operator, ok := rsqlComparators[comparator]; ok {
+		return operator, nil
+	}
+	return rest[1:end], nil
+}
+
+func isOperatorName(name string) bool {
+	for i, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// arguments return escaped value or comma joined escaped values of list,
+// so list is one value of selector
+func (r *rsqlParser) arguments() (string, error) {
+	if r.peek() != '(' {
+		value, err := r.value()
+		if err != nil {
+			return "", err
+		}
+		return escapeItem(value), nil
+	}
+	r.pos++
+
+	var values []string
+	for {
+		r.skipSpace()
+		value, err := r.value()
+		if err != nil {
+			return "", err
+		}
+		values = append(values, escapeItem(value))
+
+		switch r.skipSpace(); r.peek() {
+		case ',':
+			r.pos++
+		case ')':
+			r.pos++
+			return strings.Join(values, ","), nil
+		default:
+			return "", r.errorf("expect , or ) but got %s", r.got())
+		}
+	}
+}
+
+// value return quoted or unreserved value at position
+func (r *rsqlParser) value() (string, error) {
+	quote := r.peek()
+	if quote != '"' && quote != '\'' {
+		value := r.word()
+		if value == "" {
+			return "", r.errorf("expect argument but got %s", r.got())
+		}
+		return value, nil
+	}
+
+	var (
+		b     strings.Builder
+		start = r.pos
+	)
+	for r.pos++; r.pos < len(r.input); r.pos++ {
+		switch c := r.input[r.pos]; c {
+		case '\\':
+			if r.pos+1 == len(r.input) {
+				return "", r.errorf("nothing to escape")
+			}
+			r.pos++
+			b.WriteByte(r.input[r.pos])
+		case quote:
+			r.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	r.pos = start
+	return "", r.errorf("unterminated string")
+}
@@ -0,0 +1,109 @@
+package queryparser
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+)
+
+// LintWarning describe regex key that can match more than intended
+type LintWarning struct {
+	Key string
+
+	Message string
+}
+
+func (l LintWarning) String() string {
+	return fmt.Sprintf("%s: %s", l.Key, l.Message)
+}
+
+// Lint check regex keys of schema and return warnings
+// about patterns that can match more than intended
+func (s ParseSchema) Lint() []LintWarning {
+	var warnings []LintWarning
+
+	for key, item := range s {
+		if !item.IsRegex {
+			continue
+		}
+
+		seen := map[string]bool{}
+		warn := func(format string, args ...interface{}) {
+			message := fmt.Sprintf(format, args...)
+			if seen[message] {
+				return
+			}
+			seen[message] = true
+
+			warnings = append(warnings, LintWarning{Key: key, Message: message})
+		}
+
+		re, err := syntax.Parse(key, syntax.Perl)
+		if err != nil {
+			warn("pattern does not compile: %v", err)
+			continue
+		}
+
+		if item.PartialMatch && !isAnchored(re) {
+			warn("partial match: pattern can match inside longer field names")
+		}
+
+		lintAnyChar(re, false, warn)
+
+		regex := regexp.MustCompile(pattern(key, item))
+		if regex.MatchString("") {
+			warn("pattern matches empty field name")
+		}
+
+		for direct, directItem := range s {
+			if !directItem.IsRegex && regex.MatchString(direct) {
+				warn("pattern matches key %q, regex items match first", direct)
+			}
+		}
+	}
+
+	sort.Slice(
+		warnings,
+		func(i, j int) bool {
+			if warnings[i].Key != warnings[j].Key {
+				return warnings[i].Key < warnings[j].Key
+			}
+			return warnings[i].Message < warnings[j].Message
+		},
+	)
+
+	return warnings
+}
+
+// isAnchored check that pattern starts and ends with anchors
+func isAnchored(re *syntax.Regexp) bool {
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 {
+		return false
+	}
+
+	first, last := re.Sub[0].Op, re.Sub[len(re.Sub)-1].Op
+
+	return (first == syntax.OpBeginText || first == syntax.OpBeginLine) &&
+		(last == syntax.OpEndText || last == syntax.OpEndLine)
+}
+
+func lintAnyChar(re *syntax.Regexp, repeated bool, warn func(format string, args ...interface{})) {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		if repeated {
+			warn("unbounded any char repetition also matches brackets and separators, prefer narrow class like [^\\]]* or \\w+")
+		} else {
+			warn("unescaped '.' matches any character, escape it if it should be literal")
+		}
+		return
+	case syntax.OpStar, syntax.OpPlus:
+		repeated = true
+	case syntax.OpRepeat:
+		repeated = repeated || re.Max == -1
+	}
+
+	for _, sub := range re.Sub {
+		lintAnyChar(sub, repeated, warn)
+	}
+}
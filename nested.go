@@ -0,0 +1,248 @@
+package queryparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxDepth is max nesting depth of Parser with zero MaxDepth
+const DefaultMaxDepth = 5
+
+// NestingTooDeep matches error of nested field deeper than MaxDepth
+var NestingTooDeep = errors.New("Nesting too deep")
+
+func (p *Parser) maxDepth() int {
+	if p.MaxDepth > 0 {
+		return p.MaxDepth
+	}
+
+	return DefaultMaxDepth
+}
+
+// nestedParser return parser of Nested schema of item
+// with same options as p
+func (p *Parser) nestedParser(schema ParseSchema) *Parser {
+	return &Parser{
+		ParseSchema:     schema,
+		Factory:         p.Factory,
+		Strict:          p.Strict,
+		RejectAmbiguous: p.RejectAmbiguous,
+		DuplicatePolicy: p.DuplicatePolicy,
+		MaxDepth:        p.MaxDepth,
+		depth:           p.depth + 1,
+	}
+}
+
+// compileNested create parsers of items with Nested schema
+func (p *Parser) compileNested(schema *compiledSchema) error {
+	var errs SchemaErrors
+	for _, entry := range schema.entries {
+		if entry.Item.Nested == nil {
+			continue
+		}
+
+		nested := p.nestedParser(entry.Item.Nested)
+		if err := nested.Compile(); err != nil {
+			errs = append(errs, &SchemaError{Key: entry.Key, Err: err})
+		}
+
+		if schema.nested == nil {
+			schema.nested = map[string]*Parser{}
+		}
+		schema.nested[entry.Key] = nested
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// nestedFields is fields of query grouped by items with Nested schema
+type nestedFields struct {
+	// fields of nested schema by name of item in query
+	fields map[string]url.Values
+
+	// fields deeper than MaxDepth
+	tooDeep url.Values
+}
+
+// splitNested take out fields with path of item with Nested schema
+// 	user[address][city]  user.address.city
+// fields that match ParseSchema as is are not taken
+func (p *Parser) splitNested(schema *compiledSchema, urlValues url.Values) (url.Values, nestedFields) {
+	var (
+		rest   url.Values
+		nested nestedFields
+	)
+	if schema.nested == nil {
+		return urlValues, nested
+	}
+
+	for field, values := range urlValues {
+		if _, err := p.findField(schema, field); err == nil {
+			continue
+		}
+
+		path, ok := splitPath(field)
+		if !ok {
+			continue
+		}
+
+		entry, err := p.findField(schema, path[0])
+		if err != nil || entry.Item.Nested == nil {
+			continue
+		}
+
+		if rest == nil {
+			rest = make(url.Values, len(urlValues))
+			for field, values := range urlValues {
+				rest[field] = values
+			}
+		}
+		delete(rest, field)
+
+		if p.depth+depth(path) > p.maxDepth() {
+			if nested.tooDeep == nil {
+				nested.tooDeep = url.Values{}
+			}
+			nested.tooDeep[field] = values
+			continue
+		}
+
+		if nested.fields == nil {
+			nested.fields = map[string]url.Values{}
+		}
+		if nested.fields[path[0]] == nil {
+			nested.fields[path[0]] = url.Values{}
+		}
+		nested.fields[path[0]][joinPath(path[1:])] = values
+	}
+
+	if rest == nil {
+		return urlValues, nested
+	}
+
+	return rest, nested
+}
+
+// parseNested parse fields of nested schemas to ParseResult
+// that put under name of item
+func (p *Parser) parseNested(ctx context.Context, schema *compiledSchema, nested nestedFields, result ParseResult) {
+	for field, values := range nested.tooDeep {
+		result[field] = ResultOrError{
+			Err: newFieldError(
+				"",
+				field,
+				values,
+				StageValidateField,
+				fmt.Errorf("%w, max depth is %d", NestingTooDeep, p.maxDepth()),
+			),
+			Stage:    StageValidateField,
+			Presence: PresenceValue,
+		}
+	}
+
+	for name, values := range nested.fields {
+		entry, err := p.findField(schema, name)
+		if err != nil {
+			continue
+		}
+
+		resultItem := ResultOrError{
+			Result:   schema.nested[entry.Key].ParseUrlValuesContext(ctx, values),
+			Presence: PresenceValue,
+		}
+
+		if entry.Item.FinalizeParseFunc != nil {
+			entry.Item.FinalizeParseFunc(result, name, resultItem)
+		} else {
+			result[name] = resultItem
+		}
+	}
+}
+
+// splitPath split field in bracket or dot notation to path
+// 	user[address][city] -> user, address, city
+// 	user.address.city   -> user, address, city
+// 	user[tags][]        -> user, tags, ""
+// return false if field is not nested
+func splitPath(field string) ([]string, bool) {
+	at := strings.IndexAny(field, "[.")
+	if at <= 0 {
+		return nil, false
+	}
+
+	var (
+		path = []string{field[:at]}
+		rest = field[at:]
+	)
+	for rest != "" {
+		var segment string
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, false
+			}
+			segment, rest = rest[1:end], rest[end+1:]
+		case '.':
+			end := strings.IndexAny(rest[1:], "[.")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			segment, rest = rest[1:end+1], rest[end+1:]
+			if segment == "" {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+
+		// only last segment can be empty array suffix
+		if len(path) > 1 && path[len(path)-1] == "" {
+			return nil, false
+		}
+		path = append(path, segment)
+	}
+
+	if path[1] == "" {
+		return nil, false
+	}
+
+	return path, true
+}
+
+// joinPath join path to field in bracket notation
+func joinPath(path []string) string {
+	var b strings.Builder
+	b.WriteString(path[0])
+	for _, segment := range path[1:] {
+		b.WriteString("[" + segment + "]")
+	}
+
+	return b.String()
+}
+
+// depth return nesting depth of path, array suffix is not counted
+func depth(path []string) int {
+	if path[len(path)-1] == "" {
+		return len(path) - 2
+	}
+
+	return len(path) - 1
+}
+
+// nestedField return field of nested schema as field of parent
+// 	user, address[city] -> user[address][city]
+func nestedField(parent, field string) string {
+	if at := strings.IndexByte(field, '['); at >= 0 {
+		return parent + "[" + field[:at] + "]" + field[at:]
+	}
+
+	return parent + "[" + field + "]"
+}
@@ -0,0 +1,85 @@
+package queryparser
+
+import "strings"
+
+// Presence describe if field was in query and if it value is null
+type Presence int
+
+const (
+	// PresenceAbsent is presence of field that not in query
+	PresenceAbsent Presence = iota
+
+	// PresenceNull is presence of field which values are null by NullPolicy
+	PresenceNull
+
+	// PresenceValue is presence of field with value
+	PresenceValue
+)
+
+func (p Presence) String() string {
+	switch p {
+	case PresenceAbsent:
+		return "absent"
+	case PresenceNull:
+		return "null"
+	case PresenceValue:
+		return "value"
+	}
+
+	return "unknown"
+}
+
+// NullPolicy describe which values of field are null,
+// null values are not validated and mapped
+type NullPolicy struct {
+	// Empty value is null
+	Empty bool
+
+	// Literals that are null, for example "null"
+	Literals []string
+
+	// CaseInsensitive compare of Literals
+	CaseInsensitive bool
+}
+
+// NullOrEmpty is NullPolicy where empty value and "null" are null
+var NullOrEmpty = NullPolicy{Empty: true, Literals: []string{"null"}}
+
+// IsNull return true if field have values and all of them are null
+func (n NullPolicy) IsNull(values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, value := range values {
+		if !n.isNull(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (n NullPolicy) isNull(value string) bool {
+	if value == "" {
+		return n.Empty
+	}
+
+	for _, literal := range n.Literals {
+		if literal == value || n.CaseInsensitive && strings.EqualFold(literal, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Presence return presence of field in result
+func (p ParseResult) Presence(field string) Presence {
+	value, find := p[field]
+	if !find {
+		return PresenceAbsent
+	}
+
+	return value.Presence
+}
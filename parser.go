@@ -1,9 +1,11 @@
 package queryparser
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
-	"regexp"
+	"sort"
 
 	"github.com/0B1t322/QueryParser/typemapper"
 	"github.com/0B1t322/QueryParser/validator"
@@ -21,24 +23,102 @@ type Factory interface {
 	AddQueryTypeMapperField(field string, queryType typemapper.QueryTypeMapper)
 }
 
+// KeyFactory is optional interface of Factory
+// to validate and map regex field by key that Parser found in ParseSchema
+//
+// Factory without it validate and map regex field by own matching
+// and can pick other key than Parser, so it should match keys against
+// whole field name: typemapper.QueryTypeFactory do it if WholeMatch is set
+type KeyFactory interface {
+	ValidateKey(key, field string, values []string) error
+
+	MapKey(key, field string, values []string) (interface{}, error)
+}
+
+// ContextFactory is optional interface of Factory
+// to map fields with context of parse
+type ContextFactory interface {
+	MapRegexFieldContext(ctx context.Context, field string, values []string) (interface{}, error)
+
+	MapFieldContext(ctx context.Context, field string, values []string) (interface{}, error)
+
+	MapKeyContext(ctx context.Context, key, field string, values []string) (interface{}, error)
+}
+
 type ParseSchemaItem struct {
 	// Func to validate after parse
 	ValidationFunc validator.ValidateFunc
 
+	// Func to validate after parse with named captures of regex key,
+	// runs after ValidationFunc
+	ValidationCapturesFunc validator.CapturesValidateFunc
+
+	// Func to validate after parse with context of parse,
+	// runs after ValidationCapturesFunc
+	ValidationContextFunc validator.ContextValidateFunc
+
 	// Func to map type
 	TypeMapFunc typemapper.TypeMapperFunc
 
+	// Func to map type with named captures of regex key,
+	// used instead of TypeMapFunc if set
+	TypeMapCapturesFunc typemapper.CapturesTypeMapperFunc
+
+	// Func to map type with context of parse,
+	// used instead of TypeMapFunc and TypeMapCapturesFunc if set
+	//
+	// Named captures of regex key can be taken by typemapper.CapturesFromContext
+	TypeMapContextFunc typemapper.ContextTypeMapperFunc
+
 	// Func to validate field
 	ValidateFieldFunc typemapper.ValidateFieldFunc
 
+	// Func to validate field with named captures of regex key,
+	// runs after ValidateFieldFunc
+	ValidateFieldCapturesFunc typemapper.CapturesValidateFieldFunc
+
 	// Func to validate values
 	ValidateValuesFunc typemapper.ValidateValuesFunc
 
+	// Nested schema of fields under key in bracket or dot notation
+	// 	user[address][city]=x
+	// 	user.address.city=x
+	// they are parsed to ParseResult that put under key,
+	// item with Nested and without map funcs take only nested fields
+	Nested ParseSchema
+
+	// Mapper used instead of map funcs if set,
+	// it validators run before ValidateFieldFunc and ValidateValuesFunc
+	Mapper typemapper.QueryTypeMapper
+
 	// Check if field is regex
+	//
+	// Regex key match whole field name, for example
+	// 	name\[.*\]
+	// match "name[eq]" but not "xname[eq]" or "name[eq]x"
 	IsRegex bool
 
+	// PartialMatch allow regex key to match part of field name
+	PartialMatch bool
+
+	// DuplicatePolicy of repeated field, DuplicateDefault use policy of Parser
+	DuplicatePolicy DuplicatePolicy
+
+	// NullPolicy describe values that are null,
+	// field is validated and null field is put to result with PresenceNull
+	// without validation of values and map
+	NullPolicy NullPolicy
+
 	// Finalize
 	FinalizeParseFunc	FinalizeParseFunc
+
+	// Regex items with higher priority match first,
+	// items with same priority match in order of declaration
+	// in OrderedParseSchema or in lexical order of keys
+	Priority int
+
+	// position in OrderedParseSchema
+	order int
 }
 
 type FinalizeParseFunc func(result ParseResult, field string, value ResultOrError)
@@ -48,6 +128,29 @@ type ParseSchema map[string]ParseSchemaItem
 type ResultOrError struct {
 	Err error
 	Result interface{}
+
+	// Stage of pipeline where Err happened
+	Stage Stage
+
+	// Named capture groups of regex key that matched field
+	Captures map[string]string
+
+	// Presence of field, PresenceAbsent for fields that not in query
+	Presence Presence
+}
+
+// hasMapper return false for item with only Nested schema
+func (i *ParseSchemaItem) hasMapper() bool {
+	return i.Nested == nil ||
+		i.Mapper != nil ||
+		i.TypeMapFunc != nil ||
+		i.TypeMapCapturesFunc != nil ||
+		i.TypeMapContextFunc != nil
+}
+
+// IsNull return true if field values are null by NullPolicy
+func (r ResultOrError) IsNull() bool {
+	return r.Presence == PresenceNull
 }
 
 func (r ResultOrError) IsError() bool {
@@ -56,10 +159,37 @@ func (r ResultOrError) IsError() bool {
 
 type ParseResult map[string]ResultOrError
 
+// Parser is safe for concurrent use if ParseSchema and Factory
+// are not changed after Compile
 type Parser struct {
 	ParseSchema ParseSchema
 
+	// Parser register ParseSchema in clone of Factory if Factory
+	// have method
+	// 	Clone() *typemapper.QueryTypeFactory
+	// otherwise ParseSchema is registered in Factory itself
+	// and items of first registered schema win
 	Factory Factory
+
+	// Strict parser report fields that not found in ParseSchema
+	// as UnknownFieldsError under UnknownFieldsKey
+	Strict bool
+
+	// RejectAmbiguous make parser report typemapper.AmbiguousFieldError
+	// if field match more than one regex item with same Priority
+	RejectAmbiguous bool
+
+	// DuplicatePolicy of repeated fields which items have DuplicateDefault,
+	// DuplicateDefault pass all values to mapper
+	DuplicatePolicy DuplicatePolicy
+
+	// MaxDepth of nested fields, DefaultMaxDepth if zero
+	MaxDepth int
+
+	compiled *compiledSchema
+
+	// depth of nested parser
+	depth int
 }
 
 func New(
@@ -74,31 +204,67 @@ func New(
 
 func (p *Parser) ParseUrlValues(
 	urlValues url.Values,
+) ParseResult {
+	return p.ParseUrlValuesContext(context.Background(), urlValues)
+}
+
+// ParseUrlValuesContext same as ParseUrlValues but pass ctx
+// to context funcs of ParseSchemaItem
+//
+// If ctx is done parse stops and CanceledError is put under CanceledKey
+func (p *Parser) ParseUrlValuesContext(
+	ctx context.Context,
+	urlValues url.Values,
 ) ParseResult {
 	result := ParseResult{}
 
+	var (
+		unknown  UnknownFieldsError
+		canceled *CanceledError
+	)
+
+	schema := p.schema()
+
+	urlValues, nested := p.splitNested(schema, p.mergeArrayFields(schema, urlValues))
+
 	for field, values := range urlValues {
-		var item *ParseSchemaItem
+		if canceled != nil {
+			canceled.Skipped = append(canceled.Skipped, field)
+			continue
+		} else if err := ctx.Err(); err != nil {
+			canceled = &CanceledError{Err: err, Skipped: []string{field}}
+			continue
+		}
+
+		var entry *compiledEntry
 		{
-			if findedItem, err := p.findField(field); err == nil {
-				item = findedItem
-			} else if err != nil {
+			findedEntry, err := p.findField(schema, field)
+			if err == nil && findedEntry.Item.hasMapper() {
+				entry = findedEntry
+			} else if errors.Is(err, typemapper.AmbiguousField) {
+				result[field] = ResultOrError{
+					Err:      newFieldError("", field, values, StageValidateField, err),
+					Stage:    StageValidateField,
+					Presence: PresenceValue,
+				}
+				continue
+			} else {
+				if p.Strict {
+					unknown = append(
+						unknown,
+						&UnknownFieldError{
+							Field: field,
+						},
+					)
+				}
 				continue
 			}
 		}
 
-		p.Factory.AddQueryTypeMapperField(
-			p.findFieldNameInSchema(field, item),
-			typemapper.NewCustomQueryTypeBuilder().
-			SetFieldFunc(item.ValidateFieldFunc).
-			SetValidateValuesFunc(item.ValidateValuesFunc).
-			SetTypeMapperFunc(item.TypeMapFunc).
-			MustBuild(),
-		)
-
-		resultItem := p.getResultItem(item, field, values)
+		item := &entry.Item
+		resultItem := p.getResultItem(ctx, schema.factory, entry, field, values)
 
-		if resultItem.Err != nil || resultItem.Result != nil {
+		if resultItem.Err != nil || resultItem.Result != nil || resultItem.IsNull() {
 			if item.FinalizeParseFunc != nil {
 				item.FinalizeParseFunc(result, field, resultItem)
 			} else {
@@ -107,23 +273,68 @@ func (p *Parser) ParseUrlValues(
 		}
 	}
 
+	p.parseNested(ctx, schema, nested, result)
+
+	if canceled != nil {
+		sort.Strings(canceled.Skipped)
+		result[CanceledKey] = ResultOrError{Err: canceled}
+	}
+
+	if len(unknown) > 0 {
+		sort.Slice(
+			unknown,
+			func(i, j int) bool {
+				return unknown[i].Field < unknown[j].Field
+			},
+		)
+		p.suggestUnknown(schema, unknown)
+
+		result[UnknownFieldsKey] = ResultOrError{
+			Err:   unknown,
+			Stage: StageValidateField,
+		}
+	}
+
 	return result
 }
 
-func (p *Parser) getResultItem(item *ParseSchemaItem, field string, values []string) ResultOrError {
+// getResultItem run parse pipeline and wrap it error to FieldError
+func (p *Parser) getResultItem(ctx context.Context, factory Factory, entry *compiledEntry, field string, values []string) ResultOrError {
 	var resultItem ResultOrError
 	{
-		mapResult, err := p.mapItem(item, field, values)
+		resultItem.Captures = typemapper.NamedCaptures(entry.regex, field)
+		resultItem.Presence = PresenceValue
+
+		policyValues, err := p.applyDuplicatePolicy(&entry.Item, values)
 		if err != nil {
-			resultItem.Err = err
+			resultItem.Err = newFieldError(entry.Key, field, values, StageValidateValues, err)
+			resultItem.Stage = StageValidateValues
 			return resultItem
 		}
-
-		if validate := item.ValidationFunc; validate != nil {
-			if err := validate(mapResult); err != nil {
-				resultItem.Err = err
+		values = policyValues
+
+		if entry.Item.NullPolicy.IsNull(values) {
+			// field of null is validated, values are not
+			err := p.validateItem(factory, entry, field, values)
+			if err != nil && validateStage(err) == StageValidateField {
+				resultItem.Err = newFieldError(entry.Key, field, values, StageValidateField, err)
+				resultItem.Stage = StageValidateField
 				return resultItem
 			}
+
+			resultItem.Presence = PresenceNull
+			return resultItem
+		}
+
+		if resultItem.Captures != nil {
+			ctx = typemapper.ContextWithCaptures(ctx, resultItem.Captures)
+		}
+
+		mapResult, stage, err := p.runPipeline(ctx, factory, entry, field, values, resultItem.Captures)
+		if err != nil {
+			resultItem.Err = newFieldError(entry.Key, field, values, stage, err)
+			resultItem.Stage = stage
+			return resultItem
 		}
 
 		resultItem.Result = mapResult
@@ -132,63 +343,157 @@ func (p *Parser) getResultItem(item *ParseSchemaItem, field string, values []str
 	return resultItem
 }
 
-func (p *Parser) mapItem(item *ParseSchemaItem, field string, values []string) (interface{}, error) {
-	if item.IsRegex {
-		return p.mapRegexField(field, values)
+// runPipeline run parse pipeline:
+// 	validate field, validate values, map, validate mapped value
+// and return mapped value or error with stage where it happened
+func (p *Parser) runPipeline(
+	ctx context.Context,
+	factory Factory,
+	entry *compiledEntry,
+	field string,
+	values []string,
+	captures map[string]string,
+) (interface{}, Stage, error) {
+	item := &entry.Item
+
+	if err := p.validateItem(factory, entry, field, values); err != nil {
+		return nil, validateStage(err), err
+	}
+
+	mapResult, err := p.mapItem(ctx, factory, entry, field, values)
+	if err != nil {
+		return nil, StageMap, err
+	}
+
+	if validate := item.ValidationFunc; validate != nil {
+		if err := validate(mapResult); err != nil {
+			return nil, StageValidate, err
+		}
+	}
+
+	if validate := item.ValidationCapturesFunc; validate != nil {
+		if err := validate(mapResult, captures); err != nil {
+			return nil, StageValidate, err
+		}
 	}
 
-	return p.mapDirectField(field, values)
+	if validate := item.ValidationContextFunc; validate != nil {
+		if err := validate(ctx, mapResult); err != nil {
+			return nil, StageValidate, err
+		}
+	}
+
+	return mapResult, StageNone, nil
 }
 
-func (p *Parser) mapDirectField(field string, values []string) (interface{}, error) {
-	return p.Factory.MapField(field, values)
+func (p *Parser) validateItem(factory Factory, entry *compiledEntry, field string, values []string) error {
+	if entry.Item.IsRegex {
+		if keyFactory, ok := factory.(KeyFactory); ok {
+			return keyFactory.ValidateKey(entry.Key, field, values)
+		}
+		return factory.ValidateRegexField(field, values)
+	}
+
+	return factory.Validate(field, values)
 }
 
-func (p *Parser) mapRegexField(field string, values []string) (interface{}, error) {
-	return p.Factory.MapRegexField(field, values)
+// validateStage return stage of error returned by Factory validation
+func validateStage(err error) Stage {
+	if errors.Is(err, typemapper.InvalidValues) {
+		return StageValidateValues
+	}
+
+	return StageValidateField
 }
 
+func (p *Parser) mapItem(ctx context.Context, factory Factory, entry *compiledEntry, field string, values []string) (interface{}, error) {
+	if contextFactory, ok := factory.(ContextFactory); ok {
+		if entry.Item.IsRegex {
+			return contextFactory.MapKeyContext(ctx, entry.Key, field, values)
+		}
+		return contextFactory.MapFieldContext(ctx, field, values)
+	}
 
-func (p *Parser) findRegexField(field string) (*ParseSchemaItem, error) {
-	for key, value := range p.ParseSchema {
-		if match, err := regexp.MatchString(key, field); match && value.IsRegex {
-			return &value, nil
-		} else if err != nil {
-			return nil, err
+	if entry.Item.IsRegex {
+		if keyFactory, ok := factory.(KeyFactory); ok {
+			return keyFactory.MapKey(entry.Key, field, values)
 		}
+		return p.mapRegexField(factory, field, values)
 	}
 
-	return nil, fmt.Errorf("Not found field")
+	return p.mapDirectField(factory, field, values)
 }
 
-func (p *Parser) findFieldNameInSchema(field string, item *ParseSchemaItem) (string) {
-	if item.IsRegex {
-		for key, value := range p.ParseSchema {
-			if match, _ := regexp.MatchString(key, field); match && value.IsRegex {
-				return key
+func (p *Parser) mapDirectField(factory Factory, field string, values []string) (interface{}, error) {
+	return factory.MapField(field, values)
+}
+
+func (p *Parser) mapRegexField(factory Factory, field string, values []string) (interface{}, error) {
+	return factory.MapRegexField(field, values)
+}
+
+
+func (p *Parser) findRegexField(schema *compiledSchema, field string) (*compiledEntry, error) {
+	var (
+		found   *compiledEntry
+		matched []string
+	)
+
+	// entries are sorted by Priority, so only entries with Priority
+	// of first match are checked if ambiguous fields are rejected
+	for i := range schema.entries {
+		entry := &schema.entries[i]
+		if entry.regex == nil {
+			continue
+		} else if found != nil && entry.Item.Priority < found.Item.Priority {
+			break
+		} else if !entry.regex.MatchString(field) {
+			continue
+		}
+
+		if found == nil {
+			found = entry
+			if !p.RejectAmbiguous {
+				break
 			}
+		} else {
+			matched = append(matched, entry.Key)
 		}
 	}
 
-	return field
+	if found == nil {
+		return nil, fmt.Errorf("Not found field")
+	}
+
+	if p.RejectAmbiguous && len(matched) > 0 {
+		return nil, &typemapper.AmbiguousFieldError{
+			Field: field,
+			Keys:  append([]string{found.Key}, matched...),
+		}
+	}
+
+	return found, nil
 }
 
-func (p *Parser) findDirectField(field string) (*ParseSchemaItem, error) {
-	item, find := p.ParseSchema[field]
+func (p *Parser) findDirectField(schema *compiledSchema, field string) (*compiledEntry, error) {
+	item, find := schema.direct[field]
 	if !find {
 		return nil, fmt.Errorf("Not found field")
 	}
-	return &item, nil
+	return &compiledEntry{ParseSchemaEntry: ParseSchemaEntry{Key: field, Item: item}}, nil
 }
 
-func (p *Parser) findField(field string) (*ParseSchemaItem, error) {
-	if regexItem, err := p.findRegexField(field); err == nil {
+func (p *Parser) findField(schema *compiledSchema, field string) (*compiledEntry, error) {
+	regexItem, err := p.findRegexField(schema, field)
+	if err == nil {
 		return regexItem, nil
+	} else if errors.Is(err, typemapper.AmbiguousField) {
+		return nil, err
 	}
 
-	if directItem, err := p.findDirectField(field); err == nil {
+	if directItem, err := p.findDirectField(schema, field); err == nil {
 		return directItem, nil
 	}
 
 	return nil, fmt.Errorf("Field not found")
-}
\ No newline at end of file
+}
@@ -0,0 +1,128 @@
+package queryparser_test
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+
+	queryparser "github.com/0B1t322/QueryParser"
+	"github.com/0B1t322/QueryParser/typemapper"
+)
+
+// benchSchema return schema with 40 regex and 20 direct items
+func benchSchema() queryparser.ParseSchema {
+	schema := queryparser.ParseSchema{}
+	for i := 0; i < 40; i++ {
+		schema[fmt.Sprintf(`field_%d\[(eq|ne|lt|lte|gt|gte|like)\]`, i)] = queryparser.ParseSchemaItem{
+			IsRegex: true,
+			TypeMapFunc: func(field string, values []string) (interface{}, error) {
+				return values[0], nil
+			},
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		schema[fmt.Sprintf("param_%d", i)] = queryparser.ParseSchemaItem{
+			TypeMapFunc: func(field string, values []string) (interface{}, error) {
+				return strconv.Atoi(values[0])
+			},
+		}
+	}
+
+	return schema
+}
+
+func benchQuery() url.Values {
+	query := url.Values{}
+	for i := 0; i < 40; i += 4 {
+		query.Add(fmt.Sprintf("field_%d[eq]", i), "value")
+	}
+
+	for i := 0; i < 20; i += 4 {
+		query.Add(fmt.Sprintf("param_%d", i), "10")
+	}
+
+	query.Add("unknown", "value")
+	return query
+}
+
+func BenchmarkParser(b *testing.B) {
+	query := benchQuery()
+
+	b.Run(
+		"NotCompiled",
+		func(b *testing.B) {
+			p := queryparser.New(typemapper.NewQueryTypeFactory(), benchSchema())
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p.ParseUrlValues(query)
+			}
+		},
+	)
+
+	b.Run(
+		"Compiled",
+		func(b *testing.B) {
+			p := queryparser.New(typemapper.NewQueryTypeFactory(), benchSchema()).MustCompile()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p.ParseUrlValues(query)
+			}
+		},
+	)
+}
+
+func BenchmarkQueryTypeFactory(b *testing.B) {
+	newFactory := func() *typemapper.QueryTypeFactory {
+		factory := typemapper.NewQueryTypeFactory()
+		for i := 0; i < 60; i++ {
+			factory.AddField(
+				fmt.Sprintf(`field_%d\[(eq|lte)\]`, i),
+				typemapper.NewCustomQueryTypeBuilder().
+					SetTypeMapperFunc(
+						func(field string, values []string) (interface{}, error) {
+							return values[0], nil
+						},
+					).
+					MustBuild(),
+			)
+		}
+		return factory
+	}
+
+	values := []string{"value"}
+
+	b.Run(
+		"NotCompiled",
+		func(b *testing.B) {
+			factory := newFactory()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				factory.MapRegexField("field_59[eq]", values)
+			}
+		},
+	)
+
+	b.Run(
+		"Compiled",
+		func(b *testing.B) {
+			factory := newFactory()
+			if err := factory.Compile(); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				factory.MapRegexField("field_59[eq]", values)
+			}
+		},
+	)
+}
@@ -1,15 +1,25 @@
 package queryparser_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	queryparser "github.com/0B1t322/QueryParser"
 	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/list"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/0B1t322/QueryParser/validator"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -65,6 +75,1159 @@ func TestFunc_Parser(t *testing.T) {
 	)
 }
 
+func TestFunc_ParserPipeline(t *testing.T) {
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			`age\[.*\]`: queryparser.ParseSchemaItem{
+				IsRegex: true,
+				ValidateFieldFunc: func(field string) error {
+					if field != "age[eq]" {
+						return fmt.Errorf("Bad operation")
+					}
+					return nil
+				},
+				ValidateValuesFunc: func(values []string) error {
+					if len(values) != 1 {
+						return fmt.Errorf("Expect one value")
+					}
+					return nil
+				},
+				TypeMapFunc: func(field string, values []string) (interface{}, error) {
+					return strconv.Atoi(values[0])
+				},
+				ValidationFunc: func(value interface{}) error {
+					if value.(int) < 0 {
+						return fmt.Errorf("Age can't be lower then zero")
+					}
+					return nil
+				},
+			},
+		},
+	)
+
+	t.Run(
+		"Success",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"age[eq]": {"18"}})
+			require.False(t, result["age[eq]"].IsError())
+			require.Equal(t, queryparser.StageNone, result["age[eq]"].Stage)
+			require.Equal(t, 18, result["age[eq]"].Result)
+		},
+	)
+
+	t.Run(
+		"Stages",
+		func(t *testing.T) {
+			for query, stage := range map[string]queryparser.Stage{
+				"age[lte]=18":           queryparser.StageValidateField,
+				"age[eq]=18&age[eq]=19": queryparser.StageValidateValues,
+				"age[eq]=eighteen":      queryparser.StageMap,
+				"age[eq]=-1":            queryparser.StageValidate,
+			} {
+				values, err := url.ParseQuery(query)
+				require.NoError(t, err)
+
+				result := p.ParseUrlValues(values)
+				for field := range values {
+					require.True(t, result[field].IsError(), query)
+					require.Equal(t, stage, result[field].Stage, query)
+				}
+			}
+		},
+	)
+}
+
+func TestFunc_ParserStrict(t *testing.T) {
+	mapString := func(field string, values []string) (interface{}, error) {
+		return values[0], nil
+	}
+
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			"offset": queryparser.ParseSchemaItem{
+				TypeMapFunc: mapString,
+			},
+			"limit": queryparser.ParseSchemaItem{
+				TypeMapFunc: mapString,
+			},
+			`name\[(eq|like)\]`: queryparser.ParseSchemaItem{
+				IsRegex:     true,
+				TypeMapFunc: mapString,
+			},
+		},
+	)
+
+	t.Run(
+		"NotStrict",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"ofset": {"10"}})
+			require.Empty(t, result)
+		},
+	)
+
+	p.Strict = true
+
+	t.Run(
+		"Suggestions",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(
+				url.Values{
+					"ofset":    {"10"},
+					"limit":    {"10"},
+					"nme[eq]":  {"dan"},
+					"name[lk]": {"dan"},
+					"zzzzzz":   {"1"},
+				},
+			)
+			require.False(t, result["limit"].IsError())
+			require.True(t, result[queryparser.UnknownFieldsKey].IsError())
+
+			var unknown queryparser.UnknownFieldsError
+			require.ErrorAs(t, result[queryparser.UnknownFieldsKey].Err, &unknown)
+			require.Len(t, unknown, 4)
+
+			suggestions := map[string][]string{}
+			for _, err := range unknown {
+				suggestions[err.Field] = err.Suggestions
+			}
+
+			require.Equal(t, []string{"offset"}, suggestions["ofset"])
+			require.Contains(t, suggestions["nme[eq]"], "name[eq]")
+			require.Contains(t, suggestions["name[lk]"], "name[like]")
+			require.Empty(t, suggestions["zzzzzz"])
+		},
+	)
+
+	t.Run(
+		"LongField",
+		func(t *testing.T) {
+			field := "name[" + strings.Repeat("x", 3000) + "]"
+
+			start := time.Now()
+			result := p.ParseUrlValues(url.Values{field: {"1"}})
+			require.Less(t, int64(time.Since(start)), int64(time.Second))
+
+			var unknown queryparser.UnknownFieldsError
+			require.ErrorAs(t, result[queryparser.UnknownFieldsKey].Err, &unknown)
+			require.Len(t, unknown, 1)
+			require.Empty(t, unknown[0].Suggestions)
+		},
+	)
+
+	t.Run(
+		"ManyFields",
+		func(t *testing.T) {
+			schema := queryparser.ParseSchema{}
+			for i := 0; i < 40; i++ {
+				schema[fmt.Sprintf(`field%d\[(eq|ne|lt|gt|like)\]`, i)] = queryparser.ParseSchemaItem{
+					IsRegex:     true,
+					TypeMapFunc: mapString,
+				}
+			}
+
+			many := queryparser.New(typemapper.NewQueryTypeFactory(), schema).MustCompile()
+			many.Strict = true
+
+			values := url.Values{}
+			for i := 0; i < 100; i++ {
+				values.Set(fmt.Sprintf("field%d[%s]", i, strings.Repeat("q", 50)), "1")
+			}
+			values.Set("field0[e]", "1")
+
+			start := time.Now()
+			result := many.ParseUrlValues(values)
+			require.Less(t, int64(time.Since(start)), int64(time.Second))
+
+			var unknown queryparser.UnknownFieldsError
+			require.ErrorAs(t, result[queryparser.UnknownFieldsKey].Err, &unknown)
+			require.Len(t, unknown, 101)
+
+			// only first fields get suggestions
+			require.Equal(t, "field0[e]", unknown[0].Field)
+			require.Contains(t, unknown[0].Suggestions, "field0[eq]")
+			for _, err := range unknown[10:] {
+				require.Empty(t, err.Suggestions)
+			}
+		},
+	)
+}
+
+func TestFunc_ParserRegexOrder(t *testing.T) {
+	mapKey := func(key string) queryparser.ParseSchemaItem {
+		return queryparser.ParseSchemaItem{
+			IsRegex: true,
+			TypeMapFunc: func(field string, values []string) (interface{}, error) {
+				return key, nil
+			},
+		}
+	}
+
+	t.Run(
+		"Declaration",
+		func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				p := queryparser.NewOrdered(
+					typemapper.NewQueryTypeFactory(),
+					queryparser.OrderedParseSchema{
+						{Key: `name\[eq\]`, Item: mapKey("eq")},
+						{Key: `name\[.*\]`, Item: mapKey("any")},
+					},
+				)
+
+				result := p.ParseUrlValues(url.Values{"name[eq]": {"dan"}, "name[lte]": {"dan"}})
+				require.Equal(t, "eq", result["name[eq]"].Result)
+				require.Equal(t, "any", result["name[lte]"].Result)
+			}
+		},
+	)
+
+	t.Run(
+		"Priority",
+		func(t *testing.T) {
+			lowPriority := mapKey("eq")
+			highPriority := mapKey("any")
+			highPriority.Priority = 1
+
+			for i := 0; i < 20; i++ {
+				p := queryparser.NewOrdered(
+					typemapper.NewQueryTypeFactory(),
+					queryparser.OrderedParseSchema{
+						{Key: `name\[eq\]`, Item: lowPriority},
+						{Key: `name\[.*\]`, Item: highPriority},
+					},
+				)
+
+				result := p.ParseUrlValues(url.Values{"name[eq]": {"dan"}})
+				require.Equal(t, "any", result["name[eq]"].Result)
+			}
+		},
+	)
+
+	t.Run(
+		"RejectAmbiguous",
+		func(t *testing.T) {
+			p := queryparser.New(
+				typemapper.NewQueryTypeFactory(),
+				queryparser.ParseSchema{
+					`name\[eq\]`: mapKey("eq"),
+					`name\[.*\]`: mapKey("any"),
+				},
+			)
+			p.RejectAmbiguous = true
+
+			result := p.ParseUrlValues(url.Values{"name[eq]": {"dan"}, "name[lte]": {"dan"}})
+			require.ErrorIs(t, result["name[eq]"].Err, typemapper.AmbiguousField)
+			require.Equal(t, queryparser.StageValidateField, result["name[eq]"].Stage)
+
+			var ambiguous *typemapper.AmbiguousFieldError
+			require.ErrorAs(t, result["name[eq]"].Err, &ambiguous)
+			require.Equal(t, []string{`name\[.*\]`, `name\[eq\]`}, ambiguous.Keys)
+
+			require.Equal(t, "any", result["name[lte]"].Result)
+
+			// keys with lower priority are not ambiguous
+			highPriority := mapKey("eq")
+			highPriority.Priority = 1
+			p.ParseSchema[`name\[eq\]`] = highPriority
+			p.MustCompile()
+
+			result = p.ParseUrlValues(url.Values{"name[eq]": {"dan"}})
+			require.NoError(t, result["name[eq]"].Err)
+			require.Equal(t, "eq", result["name[eq]"].Result)
+		},
+	)
+
+	t.Run(
+		"FactoryFallback",
+		func(t *testing.T) {
+			parse := func(wholeMatch bool) interface{} {
+				factory := typemapper.NewQueryTypeFactory()
+				factory.WholeMatch = wholeMatch
+
+				// Factory without KeyFactory match regex field by own rules
+				p := queryparser.NewOrdered(
+					struct{ queryparser.Factory }{factory},
+					queryparser.OrderedParseSchema{
+						{Key: `name`, Item: mapKey("name")},
+						{Key: `name\[.*\]`, Item: mapKey("any")},
+					},
+				)
+
+				return p.ParseUrlValues(url.Values{"name[eq]": {"dan"}})["name[eq]"].Result
+			}
+
+			require.Equal(t, "name", parse(false))
+			require.Equal(t, "any", parse(true))
+		},
+	)
+}
+
+func TestFunc_ParserCompile(t *testing.T) {
+	mapString := func(field string, values []string) (interface{}, error) {
+		return values[0], nil
+	}
+
+	t.Run(
+		"BadRegex",
+		func(t *testing.T) {
+			p := queryparser.New(
+				typemapper.NewQueryTypeFactory(),
+				queryparser.ParseSchema{
+					`name\[(eq|lte\]`: queryparser.ParseSchemaItem{
+						IsRegex:     true,
+						TypeMapFunc: mapString,
+					},
+					"offset": queryparser.ParseSchemaItem{
+						TypeMapFunc: mapString,
+					},
+				},
+			)
+
+			err := p.Compile()
+			require.Error(t, err)
+
+			var schemaErrors queryparser.SchemaErrors
+			require.ErrorAs(t, err, &schemaErrors)
+			require.Len(t, schemaErrors, 1)
+			require.Equal(t, `name\[(eq|lte\]`, schemaErrors[0].Key)
+
+			require.Panics(t, func() { p.MustCompile() })
+		},
+	)
+
+	t.Run(
+		"Compiled",
+		func(t *testing.T) {
+			p := queryparser.New(
+				typemapper.NewQueryTypeFactory(),
+				queryparser.ParseSchema{
+					`name\[(eq|lte)\]`: queryparser.ParseSchemaItem{
+						IsRegex:     true,
+						TypeMapFunc: mapString,
+					},
+					"offset": queryparser.ParseSchemaItem{
+						TypeMapFunc: mapString,
+					},
+				},
+			).MustCompile()
+
+			result := p.ParseUrlValues(url.Values{"name[eq]": {"dan"}, "offset": {"10"}})
+			require.Equal(t, "dan", result["name[eq]"].Result)
+			require.Equal(t, "10", result["offset"].Result)
+		},
+	)
+}
+
+func TestFunc_ParserAnchoring(t *testing.T) {
+	mapString := func(field string, values []string) (interface{}, error) {
+		return values[0], nil
+	}
+
+	schema := queryparser.ParseSchema{
+		`name\[.*\]`: queryparser.ParseSchemaItem{
+			IsRegex:     true,
+			TypeMapFunc: mapString,
+		},
+		`email\[\w+\]`: queryparser.ParseSchemaItem{
+			IsRegex:      true,
+			PartialMatch: true,
+			TypeMapFunc:  mapString,
+		},
+	}
+
+	t.Run(
+		"FullMatch",
+		func(t *testing.T) {
+			result := queryparser.New(typemapper.NewQueryTypeFactory(), schema).ParseUrlValues(
+				url.Values{
+					"name[eq]":     {"dan"},
+					"xname[eq]":    {"dan"},
+					"name[eq]junk": {"dan"},
+				},
+			)
+			require.Equal(t, "dan", result["name[eq]"].Result)
+			require.NotContains(t, result, "xname[eq]")
+			require.NotContains(t, result, "name[eq]junk")
+		},
+	)
+
+	t.Run(
+		"PartialMatch",
+		func(t *testing.T) {
+			result := queryparser.New(typemapper.NewQueryTypeFactory(), schema).ParseUrlValues(
+				url.Values{"user_email[eq]": {"dan@mail.com"}},
+			)
+			require.Equal(t, "dan@mail.com", result["user_email[eq]"].Result)
+		},
+	)
+
+	t.Run(
+		"Lint",
+		func(t *testing.T) {
+			schema := queryparser.ParseSchema{
+				`name\[.*\]`: queryparser.ParseSchemaItem{IsRegex: true},
+				`email\[\w+\]`: queryparser.ParseSchemaItem{
+					IsRegex:      true,
+					PartialMatch: true,
+				},
+				`^age\[\w+\]$`: queryparser.ParseSchemaItem{
+					IsRegex:      true,
+					PartialMatch: true,
+				},
+				`user.id`: queryparser.ParseSchemaItem{IsRegex: true},
+				`user_id`: queryparser.ParseSchemaItem{},
+			}
+
+			warnings := map[string][]string{}
+			for _, warning := range schema.Lint() {
+				warnings[warning.Key] = append(warnings[warning.Key], warning.Message)
+			}
+
+			require.Len(t, warnings[`name\[.*\]`], 1)
+			require.Len(t, warnings[`email\[\w+\]`], 1)
+			require.Len(t, warnings[`user.id`], 2)
+			require.NotContains(t, warnings, `^age\[\w+\]$`)
+		},
+	)
+}
+
+func TestFunc_ParserCaptures(t *testing.T) {
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			`(?P<field>name|email)\[(?P<op>\w+)\]`: queryparser.ParseSchemaItem{
+				IsRegex: true,
+				ValidateFieldCapturesFunc: func(field string, captures map[string]string) error {
+					if op := captures["op"]; !(op == "eq" || op == "like") {
+						return fmt.Errorf("Bad operation")
+					}
+					return nil
+				},
+				TypeMapCapturesFunc: func(field string, captures map[string]string, values []string) (interface{}, error) {
+					return FieldOperation{
+						Op:    captures["op"],
+						Value: values[0],
+					}, nil
+				},
+				ValidationCapturesFunc: func(value interface{}, captures map[string]string) error {
+					if captures["field"] == "email" && !strings.Contains(value.(FieldOperation).Value, "@") {
+						return fmt.Errorf("Bad email")
+					}
+					return nil
+				},
+				FinalizeParseFunc: func(result queryparser.ParseResult, field string, value queryparser.ResultOrError) {
+					result[value.Captures["field"]] = value
+				},
+			},
+		},
+	)
+
+	result := p.ParseUrlValues(
+		url.Values{
+			"name[like]": {"dan"},
+			"email[eq]":  {"dan"},
+		},
+	)
+	require.Equal(t, FieldOperation{Op: "like", Value: "dan"}, result["name"].Result)
+	require.Equal(t, map[string]string{"field": "name", "op": "like"}, result["name"].Captures)
+	require.Equal(t, queryparser.StageValidate, result["email"].Stage)
+
+	result = p.ParseUrlValues(url.Values{"name[lte]": {"dan"}})
+	require.Equal(t, queryparser.StageValidateField, result["name"].Stage)
+}
+
+func TestFunc_ParserConcurrent(t *testing.T) {
+	newSchema := func(prefix string) queryparser.ParseSchema {
+		return queryparser.ParseSchema{
+			`name\[(?P<op>\w+)\]`: queryparser.ParseSchemaItem{
+				IsRegex: true,
+				TypeMapCapturesFunc: func(field string, captures map[string]string, values []string) (interface{}, error) {
+					return prefix + captures["op"] + values[0], nil
+				},
+			},
+			"offset": queryparser.ParseSchemaItem{
+				TypeMapFunc: func(field string, values []string) (interface{}, error) {
+					return strconv.Atoi(values[0])
+				},
+			},
+		}
+	}
+
+	parse := func(t *testing.T, p *queryparser.Parser, prefix string) {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				result := p.ParseUrlValues(
+					url.Values{
+						"name[eq]": {strconv.Itoa(i)},
+						"offset":   {strconv.Itoa(i)},
+					},
+				)
+				assert.Equal(t, prefix+"eq"+strconv.Itoa(i), result["name[eq]"].Result)
+				assert.Equal(t, i, result["offset"].Result)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	t.Run(
+		"Compiled",
+		func(t *testing.T) {
+			p := queryparser.New(typemapper.NewQueryTypeFactory(), newSchema("")).MustCompile()
+			parse(t, p, "")
+		},
+	)
+
+	t.Run(
+		"NotCompiled",
+		func(t *testing.T) {
+			p := queryparser.New(typemapper.NewQueryTypeFactory(), newSchema(""))
+			parse(t, p, "")
+		},
+	)
+
+	t.Run(
+		"SharedFactory",
+		func(t *testing.T) {
+			factory := typemapper.NewQueryTypeFactory()
+
+			var wg sync.WaitGroup
+			for _, prefix := range []string{"first", "second", "third"} {
+				wg.Add(1)
+				go func(prefix string) {
+					defer wg.Done()
+					parse(t, queryparser.New(factory, newSchema(prefix)), prefix)
+				}(prefix)
+			}
+			wg.Wait()
+
+			require.Empty(t, factory.Querys)
+		},
+	)
+}
+
+func TestFunc_ParserContext(t *testing.T) {
+	type userKey struct{}
+
+	newParser := func(cancel context.CancelFunc) *queryparser.Parser {
+		return queryparser.New(
+			typemapper.NewQueryTypeFactory(),
+			queryparser.ParseSchema{
+				`user\[(?P<op>\w+)\]`: queryparser.ParseSchemaItem{
+					IsRegex: true,
+					TypeMapContextFunc: func(ctx context.Context, field string, values []string) (interface{}, error) {
+						if cancel != nil {
+							cancel()
+						}
+
+						return fmt.Sprintf(
+							"%s:%s:%s",
+							ctx.Value(userKey{}),
+							typemapper.CapturesFromContext(ctx)["op"],
+							values[0],
+						), nil
+					},
+					ValidationContextFunc: validator.ValidateFunc(
+						func(value interface{}) error {
+							if value == "" {
+								return fmt.Errorf("Empty value")
+							}
+							return nil
+						},
+					).ToContext(),
+				},
+				"offset": queryparser.ParseSchemaItem{
+					TypeMapFunc: func(field string, values []string) (interface{}, error) {
+						return strconv.Atoi(values[0])
+					},
+				},
+			},
+		)
+	}
+
+	t.Run(
+		"Value",
+		func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), userKey{}, "admin")
+
+			result := newParser(nil).ParseUrlValuesContext(ctx, url.Values{"user[eq]": {"dan"}})
+			require.Equal(t, "admin:eq:dan", result["user[eq]"].Result)
+		},
+	)
+
+	t.Run(
+		"Canceled",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			result := newParser(nil).ParseUrlValuesContext(ctx, url.Values{"user[eq]": {"dan"}, "offset": {"1"}})
+			require.Len(t, result, 1)
+			require.ErrorIs(t, result[queryparser.CanceledKey].Err, context.Canceled)
+
+			var canceled *queryparser.CanceledError
+			require.ErrorAs(t, result[queryparser.CanceledKey].Err, &canceled)
+			require.Equal(t, []string{"offset", "user[eq]"}, canceled.Skipped)
+		},
+	)
+
+	t.Run(
+		"CanceledByMapper",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			result := newParser(cancel).ParseUrlValuesContext(
+				ctx,
+				url.Values{"user[eq]": {"dan"}, "user[like]": {"dan"}, "offset": {"1"}},
+			)
+			require.ErrorIs(t, result[queryparser.CanceledKey].Err, context.Canceled)
+
+			var canceled *queryparser.CanceledError
+			require.ErrorAs(t, result[queryparser.CanceledKey].Err, &canceled)
+			require.Equal(t, len(result)-1+len(canceled.Skipped), 3)
+		},
+	)
+}
+
+func TestFunc_ParserFieldError(t *testing.T) {
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			`age\[(eq|lte)\]`: queryparser.ParseSchemaItem{
+				IsRegex: true,
+				ValidateFieldFunc: func(field string) error {
+					if field != "age[eq]" {
+						return fmt.Errorf("Bad operation")
+					}
+					return nil
+				},
+				ValidateValuesFunc: func(values []string) error {
+					if len(values) != 1 {
+						return fmt.Errorf("Expect one value")
+					}
+					return nil
+				},
+				TypeMapFunc: func(field string, values []string) (interface{}, error) {
+					return strconv.Atoi(values[0])
+				},
+				ValidationFunc: func(value interface{}) error {
+					if value.(int) < 0 {
+						return fmt.Errorf("Age can't be lower then zero")
+					}
+					return nil
+				},
+			},
+		},
+	)
+	p.Strict = true
+
+	for query, code := range map[string]queryparser.ErrorCode{
+		"age[lte]=18":           queryparser.CodeInvalidField,
+		"age[eq]=18&age[eq]=19": queryparser.CodeInvalidValues,
+		"age[eq]=eighteen":      queryparser.CodeInvalidValue,
+		"age[eq]=-1":            queryparser.CodeValidationFailed,
+	} {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+
+		for field, item := range p.ParseUrlValues(values) {
+			err := item.Err
+			require.ErrorIs(t, err, code, query)
+
+			var fieldError *queryparser.FieldError
+			require.ErrorAs(t, err, &fieldError)
+			require.Equal(t, field, fieldError.Field)
+			require.Equal(t, `age\[(eq|lte)\]`, fieldError.Key)
+			require.Equal(t, values[field], fieldError.Values)
+			require.Equal(t, code, fieldError.Code)
+		}
+	}
+
+	result := p.ParseUrlValues(url.Values{"age[eq]": {"eighteen"}})
+	require.ErrorIs(t, result["age[eq]"].Err, strconv.ErrSyntax)
+	require.Equal(t, queryparser.StageMap, result["age[eq]"].Err.(*queryparser.FieldError).Stage)
+
+	result = p.ParseUrlValues(url.Values{"ag[eq]": {"18"}})
+	require.ErrorIs(t, result[queryparser.UnknownFieldsKey].Err, queryparser.CodeUnknownField)
+
+	var unknown queryparser.UnknownFieldsError
+	require.ErrorAs(t, result[queryparser.UnknownFieldsKey].Err, &unknown)
+	require.Equal(t, queryparser.CodeUnknownField, unknown[0].FieldError().Code)
+}
+
+func TestFunc_ParseResultErrors(t *testing.T) {
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			"offset": queryparser.ParseSchemaItem{
+				TypeMapFunc: func(field string, values []string) (interface{}, error) {
+					return strconv.Atoi(values[0])
+				},
+			},
+			"limit": queryparser.ParseSchemaItem{
+				TypeMapFunc: func(field string, values []string) (interface{}, error) {
+					return strconv.Atoi(values[0])
+				},
+				FinalizeParseFunc: func(result queryparser.ParseResult, field string, value queryparser.ResultOrError) {
+					if !value.IsError() && value.Result.(int) > 100 {
+						value.Err = fmt.Errorf("Limit is too big")
+						value.Stage = queryparser.StageValidate
+					}
+					result[field] = value
+				},
+			},
+		},
+	)
+	p.Strict = true
+
+	t.Run(
+		"NoErrors",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"offset": {"1"}, "limit": {"10"}})
+			require.False(t, result.HasErrors())
+			require.Empty(t, result.Errors())
+			require.NoError(t, result.Err())
+			require.Nil(t, result.Problem())
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"offset": {"one"}, "limit": {"1000"}, "ofset": {"1"}})
+			require.True(t, result.HasErrors())
+
+			errs := result.Errors()
+			require.Len(t, errs, 3)
+			require.Equal(t, "limit", errs[0].Field)
+			require.Equal(t, queryparser.CodeValidationFailed, errs[0].Code)
+			require.Equal(t, "offset", errs[1].Field)
+			require.Equal(t, queryparser.CodeInvalidValue, errs[1].Code)
+			require.Equal(t, "ofset", errs[2].Field)
+			require.Equal(t, queryparser.CodeUnknownField, errs[2].Code)
+
+			err := result.Err()
+			require.ErrorIs(t, err, strconv.ErrSyntax)
+			require.ErrorIs(t, err, queryparser.CodeUnknownField)
+			require.False(t, errors.Is(err, queryparser.CodeDuplicateValues))
+
+			var fieldError *queryparser.FieldError
+			require.ErrorAs(t, err, &fieldError)
+			require.Equal(t, errs[0], fieldError)
+		},
+	)
+
+	t.Run(
+		"Problem",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"offset": {"one"}})
+
+			recorder := httptest.NewRecorder()
+			require.NoError(t, result.Problem().Write(recorder))
+
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+			require.Equal(t, queryparser.ProblemContentType, recorder.Header().Get("Content-Type"))
+			require.JSONEq(
+				t,
+				`{
+					"type": "about:blank",
+					"title": "Invalid query parameters",
+					"status": 400,
+					"invalid-params": [
+						{
+							"name": "offset",
+							"reason": "strconv.Atoi: parsing \"one\": invalid syntax",
+							"code": "invalid_value"
+						}
+					]
+				}`,
+				recorder.Body.String(),
+			)
+		},
+	)
+}
+
+func TestFunc_ParserMapper(t *testing.T) {
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			"limit": queryparser.ParseSchemaItem{
+				Mapper: scalar.Int().SetMin(1).SetMax(100),
+			},
+			`since\[(?P<op>gt|lt)\]`: queryparser.ParseSchemaItem{
+				IsRegex: true,
+				Mapper:  scalar.Time().SetLayouts(scalar.DateOnly),
+			},
+		},
+	).MustCompile()
+
+	t.Run(
+		"Map",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"limit": {"10"}, "since[gt]": {"2021-05-01"}})
+			require.False(t, result.HasErrors())
+			require.Equal(t, 10, result["limit"].Result)
+			require.Equal(t, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), result["since[gt]"].Result)
+			require.Equal(t, map[string]string{"op": "gt"}, result["since[gt]"].Captures)
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"limit": {"1", "2"}, "since[lt]": {"may"}})
+			require.Equal(t, queryparser.StageValidateValues, result["limit"].Stage)
+			require.ErrorIs(t, result["limit"].Err, scalar.ExpectOneValue)
+			require.Equal(t, queryparser.StageMap, result["since[lt]"].Stage)
+			require.ErrorIs(t, result["since[lt]"].Err, scalar.BadSyntax)
+
+			result = p.ParseUrlValues(url.Values{"limit": {"1000"}})
+			require.ErrorIs(t, result["limit"].Err, scalar.OutOfRange)
+		},
+	)
+}
+
+func TestFunc_ParserList(t *testing.T) {
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			"id": queryparser.ParseSchemaItem{
+				Mapper: list.Of(scalar.Int()).SetMaxItems(3),
+			},
+			"tag": queryparser.ParseSchemaItem{
+				Mapper: list.Of(scalar.String()).SetEncodings(list.Separated),
+			},
+		},
+	).MustCompile()
+
+	for name, query := range map[string]string{
+		"Repeated":  "id=1&id=2",
+		"Separated": "id=1,2",
+		"Brackets":  "id[]=1&id[]=2",
+		"Mixed":     "id=1&id[]=2",
+	} {
+		t.Run(
+			name,
+			func(t *testing.T) {
+				values, err := url.ParseQuery(query)
+				require.NoError(t, err)
+
+				result := p.ParseUrlValues(values)
+				require.False(t, result.HasErrors())
+				require.Equal(t, []interface{}{1, 2}, result["id"].Result)
+				require.NotContains(t, result, "id[]")
+			},
+		)
+	}
+
+	t.Run(
+		"BracketsNotAccepted",
+		func(t *testing.T) {
+			p.Strict = true
+			defer func() { p.Strict = false }()
+
+			result := p.ParseUrlValues(url.Values{"tag[]": {"a"}})
+			require.ErrorIs(t, result.Err(), queryparser.CodeUnknownField)
+		},
+	)
+
+	t.Run(
+		"TooManyItems",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"id": {"1,2"}, "id[]": {"3", "4"}})
+			require.Equal(t, queryparser.StageValidateValues, result["id"].Stage)
+			require.ErrorIs(t, result["id"].Err, list.TooManyItems)
+		},
+	)
+}
+
+func TestFunc_ParserNull(t *testing.T) {
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{
+			"deleted_at": queryparser.ParseSchemaItem{
+				Mapper:     scalar.Time(),
+				NullPolicy: queryparser.NullOrEmpty,
+			},
+			"owner": queryparser.ParseSchemaItem{
+				Mapper: scalar.String(),
+				NullPolicy: queryparser.NullPolicy{
+					Literals:        []string{"null", "none"},
+					CaseInsensitive: true,
+				},
+			},
+			"limit": queryparser.ParseSchemaItem{
+				Mapper: scalar.Int(),
+			},
+			`name\[(?P<op>\w+)\]`: queryparser.ParseSchemaItem{
+				IsRegex:    true,
+				Mapper:     scalar.String(),
+				NullPolicy: queryparser.NullOrEmpty,
+				ValidateFieldCapturesFunc: func(field string, captures map[string]string) error {
+					if captures["op"] != "eq" {
+						return fmt.Errorf("Unknown operator %q", captures["op"])
+					}
+					return nil
+				},
+			},
+		},
+	).MustCompile()
+
+	for name, values := range map[string][]string{
+		"Empty":   {""},
+		"Literal": {"null"},
+	} {
+		t.Run(
+			name,
+			func(t *testing.T) {
+				result := p.ParseUrlValues(url.Values{"deleted_at": values})
+				require.False(t, result.HasErrors())
+				require.Equal(t, queryparser.PresenceNull, result.Presence("deleted_at"))
+				require.True(t, result["deleted_at"].IsNull())
+				require.Nil(t, result["deleted_at"].Result)
+			},
+		)
+	}
+
+	t.Run(
+		"Absent",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{})
+			require.Equal(t, queryparser.PresenceAbsent, result.Presence("deleted_at"))
+		},
+	)
+
+	t.Run(
+		"Value",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"deleted_at": {"2021-05-01T00:00:00Z"}, "limit": {"x"}})
+			require.Equal(t, queryparser.PresenceValue, result.Presence("deleted_at"))
+			require.False(t, result["deleted_at"].IsNull())
+
+			// field with error is present
+			require.Equal(t, queryparser.PresenceValue, result.Presence("limit"))
+		},
+	)
+
+	t.Run(
+		"Literals",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"owner": {"NONE"}})
+			require.Equal(t, queryparser.PresenceNull, result.Presence("owner"))
+
+			// empty value is not null for owner
+			result = p.ParseUrlValues(url.Values{"owner": {""}})
+			require.ErrorIs(t, result["owner"].Err, scalar.EmptyValue)
+
+			// not all values are null
+			result = p.ParseUrlValues(url.Values{"owner": {"null", "me"}})
+			require.Equal(t, queryparser.PresenceValue, result.Presence("owner"))
+		},
+	)
+
+	t.Run(
+		"ValidateField",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"name[eq]": {"null"}, "name[bogus]": {"null"}})
+			require.Equal(t, queryparser.PresenceNull, result.Presence("name[eq]"))
+
+			require.Error(t, result["name[bogus]"].Err)
+			require.Equal(t, queryparser.StageValidateField, result["name[bogus]"].Stage)
+			require.NotEqual(t, queryparser.PresenceNull, result.Presence("name[bogus]"))
+		},
+	)
+}
+
+func TestFunc_ParserDuplicatePolicy(t *testing.T) {
+	newParser := func(policy queryparser.DuplicatePolicy) *queryparser.Parser {
+		p := queryparser.New(
+			typemapper.NewQueryTypeFactory(),
+			queryparser.ParseSchema{
+				"role": queryparser.ParseSchemaItem{
+					TypeMapFunc: func(field string, values []string) (interface{}, error) {
+						return strings.Join(values, ","), nil
+					},
+				},
+				"tag": queryparser.ParseSchemaItem{
+					TypeMapFunc: func(field string, values []string) (interface{}, error) {
+						return strings.Join(values, ","), nil
+					},
+					DuplicatePolicy: queryparser.DuplicateAll,
+				},
+			},
+		)
+		p.DuplicatePolicy = policy
+		return p.MustCompile()
+	}
+
+	query := url.Values{"role": {"user", "admin"}, "tag": {"a", "b"}}
+
+	for policy, expect := range map[queryparser.DuplicatePolicy]string{
+		queryparser.DuplicateDefault: "user,admin",
+		queryparser.DuplicateAll:     "user,admin",
+		queryparser.DuplicateFirst:   "user",
+		queryparser.DuplicateLast:    "admin",
+	} {
+		t.Run(
+			policy.String(),
+			func(t *testing.T) {
+				result := newParser(policy).ParseUrlValues(query)
+				require.False(t, result.HasErrors())
+				require.Equal(t, expect, result["role"].Result)
+				require.Equal(t, "a,b", result["tag"].Result)
+			},
+		)
+	}
+
+	t.Run(
+		"Reject",
+		func(t *testing.T) {
+			result := newParser(queryparser.DuplicateReject).ParseUrlValues(query)
+			require.Equal(t, "a,b", result["tag"].Result)
+
+			err := result["role"].Err
+			require.ErrorIs(t, err, queryparser.DuplicateValues)
+			require.ErrorIs(t, err, queryparser.CodeDuplicateValues)
+			require.Equal(t, queryparser.StageValidateValues, result["role"].Stage)
+			require.EqualError(t, err, `Field "role": Duplicate values: field is repeated 2 times, expect one value`)
+
+			result = newParser(queryparser.DuplicateReject).ParseUrlValues(url.Values{"role": {"user"}})
+			require.Equal(t, "user", result["role"].Result)
+		},
+	)
+}
+
+func TestFunc_ParserNested(t *testing.T) {
+	schema := queryparser.ParseSchema{
+		"user": queryparser.ParseSchemaItem{
+			Nested: queryparser.ParseSchema{
+				"name": queryparser.ParseSchemaItem{
+					Mapper: scalar.String(),
+				},
+				"age": queryparser.ParseSchemaItem{
+					Mapper: scalar.Int(),
+				},
+				"tags": queryparser.ParseSchemaItem{
+					Mapper: list.Of(scalar.String()),
+				},
+				"address": queryparser.ParseSchemaItem{
+					Nested: queryparser.ParseSchema{
+						"city": queryparser.ParseSchemaItem{
+							Mapper: scalar.String(),
+						},
+					},
+				},
+			},
+		},
+		"user.name": queryparser.ParseSchemaItem{
+			Mapper: scalar.String(),
+		},
+		"limit": queryparser.ParseSchemaItem{
+			Mapper: scalar.Int(),
+		},
+	}
+
+	p := queryparser.New(typemapper.NewQueryTypeFactory(), schema).MustCompile()
+
+	t.Run(
+		"Notations",
+		func(t *testing.T) {
+			for _, query := range []string{
+				"user[age]=30&user[address][city]=Moscow&user[tags][]=a&user[tags][]=b",
+				"user.age=30&user.address.city=Moscow&user.tags=a,b",
+				"user[age]=30&user[address].city=Moscow&user.tags[]=a&user.tags=b",
+			} {
+				values, err := url.ParseQuery(query)
+				require.NoError(t, err)
+
+				result := p.ParseUrlValues(values)
+				require.False(t, result.HasErrors(), query)
+
+				user := result["user"].Result.(queryparser.ParseResult)
+				require.Equal(t, 30, user["age"].Result, query)
+				require.Len(t, user["tags"].Result, 2, query)
+
+				address := user["address"].Result.(queryparser.ParseResult)
+				require.Equal(t, "Moscow", address["city"].Result, query)
+			}
+		},
+	)
+
+	t.Run(
+		"ExactKeyWins",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"user.name": {"bob"}, "limit": {"1"}})
+			require.Equal(t, "bob", result["user.name"].Result)
+			require.NotContains(t, result, "user")
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			strict := queryparser.New(typemapper.NewQueryTypeFactory(), schema)
+			strict.Strict = true
+
+			result := strict.ParseUrlValues(url.Values{
+				"user[age]":           {"old"},
+				"user[address][town]": {"Moscow"},
+				"user":                {"bob"},
+			})
+			require.True(t, result.HasErrors())
+
+			errs := result.Errors()
+			require.Len(t, errs, 3)
+			require.Equal(t, "user", errs[0].Field)
+			require.Equal(t, queryparser.CodeUnknownField, errs[0].Code)
+			require.Equal(t, "user[address][town]", errs[1].Field)
+			require.Equal(t, queryparser.CodeUnknownField, errs[1].Code)
+			require.Equal(t, "user[age]", errs[2].Field)
+			require.Equal(t, queryparser.CodeInvalidValue, errs[2].Code)
+		},
+	)
+
+	t.Run(
+		"MaxDepth",
+		func(t *testing.T) {
+			shallow := queryparser.New(typemapper.NewQueryTypeFactory(), schema)
+			shallow.MaxDepth = 1
+
+			result := shallow.MustCompile().ParseUrlValues(url.Values{
+				"user[age]":           {"30"},
+				"user[address][city]": {"Moscow"},
+			})
+			require.Equal(t, 30, result["user"].Result.(queryparser.ParseResult)["age"].Result)
+			require.ErrorIs(t, result["user[address][city]"].Err, queryparser.NestingTooDeep)
+		},
+	)
+
+	t.Run(
+		"Compile",
+		func(t *testing.T) {
+			bad := queryparser.New(
+				typemapper.NewQueryTypeFactory(),
+				queryparser.ParseSchema{
+					"user": queryparser.ParseSchemaItem{
+						Nested: queryparser.ParseSchema{
+							"name[": queryparser.ParseSchemaItem{IsRegex: true, Mapper: scalar.String()},
+						},
+					},
+				},
+			)
+
+			var schemaErrs queryparser.SchemaErrors
+			require.ErrorAs(t, bad.Compile(), &schemaErrs)
+			require.Equal(t, "user", schemaErrs[0].Key)
+		},
+	)
+}
+
 // Code below show how to parse with recirsive with diffucal user schemas
 type FieldOperation struct {
 	Op string
@@ -0,0 +1,67 @@
+package queryparser
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType is content type of ProblemDetails
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails describe errors of ParseResult in RFC 7807 format
+type ProblemDetails struct {
+	Type string `json:"type"`
+
+	Title string `json:"title"`
+
+	Status int `json:"status"`
+
+	Detail string `json:"detail,omitempty"`
+
+	Instance string `json:"instance,omitempty"`
+
+	InvalidParams []InvalidParam `json:"invalid-params"`
+}
+
+// InvalidParam describe error of one query field
+type InvalidParam struct {
+	Name string `json:"name"`
+
+	Reason string `json:"reason"`
+
+	Code ErrorCode `json:"code"`
+}
+
+// Problem return ProblemDetails with invalid param for each failed field
+// or nil if result don't have errors
+func (r ParseResult) Problem() *ProblemDetails {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	problem := &ProblemDetails{
+		Type:          "about:blank",
+		Title:         "Invalid query parameters",
+		Status:        http.StatusBadRequest,
+		InvalidParams: make([]InvalidParam, len(errs)),
+	}
+
+	for i, err := range errs {
+		problem.InvalidParams[i] = InvalidParam{
+			Name:   err.Field,
+			Reason: err.Err.Error(),
+			Code:   err.Code,
+		}
+	}
+
+	return problem
+}
+
+// Write write ProblemDetails as response with ProblemContentType
+func (p *ProblemDetails) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+
+	return json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,118 @@
+package queryparser
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ParseErrors is collection of FieldError of one ParseResult
+type ParseErrors []*FieldError
+
+func (p ParseErrors) Error() string {
+	messages := make([]string, len(p))
+	for i, err := range p {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is report that some error of collection match target,
+// it is used by errors.Is
+func (p ParseErrors) Is(target error) bool {
+	for _, err := range p {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As set target to first error of collection that match it,
+// it is used by errors.As
+func (p ParseErrors) As(target interface{}) bool {
+	for _, err := range p {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasErrors report that some field of result or of nested result is error
+func (r ParseResult) HasErrors() bool {
+	for _, item := range r {
+		if item.IsError() {
+			return true
+		} else if nested, ok := item.Result.(ParseResult); ok && nested.HasErrors() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Errors return errors of result as FieldError sorted by field,
+// UnknownFieldsError is expanded to error of each unknown field
+//
+// Errors of nested result have field in bracket notation
+// 	user[address][city]
+func (r ParseResult) Errors() ParseErrors {
+	var errs ParseErrors
+	for key, item := range r {
+		if nested, ok := item.Result.(ParseResult); ok && !item.IsError() {
+			for _, err := range nested.Errors() {
+				nestedErr := *err
+				nestedErr.Field = nestedField(key, err.Field)
+				errs = append(errs, &nestedErr)
+			}
+			continue
+		} else if !item.IsError() {
+			continue
+		}
+
+		var (
+			unknown    UnknownFieldsError
+			fieldError *FieldError
+		)
+		switch {
+		case errors.As(item.Err, &unknown):
+			for _, err := range unknown {
+				errs = append(errs, err.FieldError())
+			}
+		case errors.As(item.Err, &fieldError):
+			errs = append(errs, fieldError)
+		default:
+			errs = append(
+				errs,
+				&FieldError{
+					Field: key,
+					Stage: item.Stage,
+					Code:  errorCode(item.Stage, item.Err),
+					Err:   item.Err,
+				},
+			)
+		}
+	}
+
+	sort.SliceStable(
+		errs,
+		func(i, j int) bool {
+			return errs[i].Field < errs[j].Field
+		},
+	)
+
+	return errs
+}
+
+// Err return ParseErrors of result or nil if result don't have errors
+func (r ParseResult) Err() error {
+	if errs := r.Errors(); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
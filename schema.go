@@ -0,0 +1,60 @@
+package queryparser
+
+import "sort"
+
+// ParseSchemaEntry is ParseSchemaItem with it key
+type ParseSchemaEntry struct {
+	Key string
+
+	Item ParseSchemaItem
+}
+
+// OrderedParseSchema describe schema where regex items
+// with same Priority match in order of declaration
+type OrderedParseSchema []ParseSchemaEntry
+
+// ParseSchema convert OrderedParseSchema to ParseSchema
+// that keep order of declaration
+func (o OrderedParseSchema) ParseSchema() ParseSchema {
+	schema := make(ParseSchema, len(o))
+	for i, entry := range o {
+		entry.Item.order = i + 1
+		schema[entry.Key] = entry.Item
+	}
+
+	return schema
+}
+
+// NewOrdered create Parser from OrderedParseSchema
+func NewOrdered(
+	Factory Factory,
+	Schema OrderedParseSchema,
+) *Parser {
+	return New(Factory, Schema.ParseSchema())
+}
+
+// ordered return regex entries in order of matching and then direct entries
+func (s ParseSchema) ordered() []ParseSchemaEntry {
+	entries := make([]ParseSchemaEntry, 0, len(s))
+	for key, item := range s {
+		entries = append(entries, ParseSchemaEntry{Key: key, Item: item})
+	}
+
+	sort.Slice(
+		entries,
+		func(i, j int) bool {
+			a, b := entries[i].Item, entries[j].Item
+			switch {
+			case a.IsRegex != b.IsRegex:
+				return a.IsRegex
+			case a.Priority != b.Priority:
+				return a.Priority > b.Priority
+			case a.order != b.order:
+				return a.order < b.order
+			}
+			return entries[i].Key < entries[j].Key
+		},
+	)
+
+	return entries
+}
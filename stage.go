@@ -0,0 +1,38 @@
+package queryparser
+
+// Stage describe step of parse pipeline
+type Stage int
+
+const (
+	// StageNone is stage of successfully parsed field
+	StageNone Stage = iota
+
+	// StageValidateField is stage where field format validates
+	StageValidateField
+
+	// StageValidateValues is stage where raw values validates
+	StageValidateValues
+
+	// StageMap is stage where values maps to user type
+	StageMap
+
+	// StageValidate is stage where mapped value validates by ValidationFunc
+	StageValidate
+)
+
+func (s Stage) String() string {
+	switch s {
+	case StageNone:
+		return "none"
+	case StageValidateField:
+		return "validate_field"
+	case StageValidateValues:
+		return "validate_values"
+	case StageMap:
+		return "map"
+	case StageValidate:
+		return "validate"
+	}
+
+	return "unknown"
+}
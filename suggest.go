@@ -0,0 +1,315 @@
+package queryparser
+
+import (
+	"regexp/syntax"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// maxSuggestions is count of suggestions returned for unknown field
+	maxSuggestions = 3
+
+	// maxSamples limit count of samples generated for one regex key
+	maxSamples = 16
+
+	// maxSuggestLength is length in runes of longest field that get suggestions,
+	// suggestions for longer fields are too expensive
+	maxSuggestLength = 64
+
+	// maxCandidates limit count of one edit candidates for one regex key
+	maxCandidates = 4096
+
+	// maxSuggestedFields is count of unknown fields of one parse that get suggestions
+	maxSuggestedFields = 10
+
+	// candidatesBudget limit count of one edit candidates matched in one parse
+	candidatesBudget = 16384
+)
+
+type suggestion struct {
+	name     string
+	distance int
+}
+
+// suggest return names closest to unknown field
+//
+// For plain keys it use edit distance to key,
+// for regex keys it use strings that match key:
+// field with one edit and samples generated from pattern.
+// Fields longer than maxSuggestLength don't get suggestions,
+// one edit candidates are taken from budget
+func (p *Parser) suggest(schema *compiledSchema, field string, budget *int) []string {
+	if utf8.RuneCountInString(field) > maxSuggestLength {
+		return nil
+	}
+
+	var (
+		limit       = maxDistance(field)
+		suggestions []suggestion
+	)
+
+	for _, entry := range schema.entries {
+		key, regex := entry.Key, entry.regex
+		if regex == nil {
+			if distance := levenshtein(field, key); distance <= limit {
+				suggestions = append(suggestions, suggestion{name: key, distance: distance})
+			}
+			continue
+		}
+
+		candidates := oneEditCandidates(field, patternAlphabet(key), *budget)
+		*budget -= len(candidates)
+
+		for _, candidate := range candidates {
+			if regex.MatchString(candidate) {
+				suggestions = append(suggestions, suggestion{name: candidate, distance: 1})
+			}
+		}
+
+		for _, sample := range regexSamples(key) {
+			if distance := levenshtein(field, sample); distance <= limit {
+				suggestions = append(suggestions, suggestion{name: sample, distance: distance})
+			}
+		}
+	}
+
+	sort.Slice(
+		suggestions,
+		func(i, j int) bool {
+			if suggestions[i].distance != suggestions[j].distance {
+				return suggestions[i].distance < suggestions[j].distance
+			}
+			return suggestions[i].name < suggestions[j].name
+		},
+	)
+
+	var names []string
+	{
+		seen := map[string]bool{}
+		for _, s := range suggestions {
+			if seen[s.name] {
+				continue
+			}
+			seen[s.name] = true
+			names = append(names, s.name)
+
+			if len(names) == maxSuggestions {
+				break
+			}
+		}
+	}
+
+	return names
+}
+
+// suggestUnknown set suggestions of first maxSuggestedFields
+// sorted unknown fields, so work of one parse is limited
+func (p *Parser) suggestUnknown(schema *compiledSchema, unknown UnknownFieldsError) {
+	budget := candidatesBudget
+	for i, err := range unknown {
+		if i == maxSuggestedFields {
+			break
+		}
+		err.Suggestions = p.suggest(schema, err.Field, &budget)
+	}
+}
+
+func maxDistance(field string) int {
+	return utf8.RuneCountInString(field)/3 + 1
+}
+
+// levenshtein return edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(
+				prev[j]+1,
+				curr[j-1]+1,
+				prev[j-1]+cost,
+			)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// oneEditCandidates return strings that differ from field by one
+// deletion, transposition, replacement or insertion of alphabet rune,
+// not more than maxCandidates and limit
+func oneEditCandidates(field string, alphabet []rune, limit int) []string {
+	if limit > maxCandidates {
+		limit = maxCandidates
+	}
+
+	var (
+		runes      = []rune(field)
+		candidates []string
+	)
+
+	for i := 0; i <= len(runes) && len(candidates) < limit; i++ {
+		left, right := runes[:i], runes[i:]
+
+		if len(right) > 0 {
+			candidates = append(candidates, string(left)+string(right[1:]))
+		}
+
+		if len(right) > 1 {
+			candidates = append(candidates, string(left)+string(right[1])+string(right[0])+string(right[2:]))
+		}
+
+		for _, r := range alphabet {
+			if len(right) > 0 && r != right[0] {
+				candidates = append(candidates, string(left)+string(r)+string(right[1:]))
+			}
+			candidates = append(candidates, string(left)+string(r)+string(right))
+		}
+	}
+
+	if len(candidates) > limit {
+		return candidates[:limit]
+	}
+	return candidates
+}
+
+// patternAlphabet return literal runes used in pattern
+func patternAlphabet(pattern string) []rune {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		alphabet []rune
+		seen     = map[rune]bool{}
+		walk     func(re *syntax.Regexp)
+	)
+	walk = func(re *syntax.Regexp) {
+		if re.Op == syntax.OpLiteral {
+			for _, r := range re.Rune {
+				if !seen[r] {
+					seen[r] = true
+					alphabet = append(alphabet, r)
+				}
+			}
+		}
+
+		for _, sub := range re.Sub {
+			walk(sub)
+		}
+	}
+	walk(re)
+
+	return alphabet
+}
+
+// regexSamples return shortest strings that match pattern
+func regexSamples(pattern string) []string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+
+	return samples(re.Simplify())
+}
+
+func samples(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return nil
+		}
+		return []string{string(classSample(re.Rune))}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return []string{"a"}
+	case syntax.OpCapture:
+		return samples(re.Sub[0])
+	case syntax.OpPlus:
+		return samples(re.Sub[0])
+	case syntax.OpRepeat:
+		return repeatSamples(samples(re.Sub[0]), re.Min)
+	case syntax.OpConcat:
+		result := []string{""}
+		for _, sub := range re.Sub {
+			result = product(result, samples(sub))
+		}
+		return result
+	case syntax.OpAlternate:
+		var result []string
+		for _, sub := range re.Sub {
+			result = append(result, samples(sub)...)
+		}
+		return limitSamples(result)
+	}
+
+	// Star, quest and empty width assertions match empty string
+	return []string{""}
+}
+
+// classSample return printable rune of char class if it exist
+func classSample(ranges []rune) rune {
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if hi < ' ' {
+			continue
+		}
+		if lo < ' ' {
+			return ' '
+		}
+		return lo
+	}
+
+	return ranges[0]
+}
+
+func repeatSamples(items []string, count int) []string {
+	result := []string{""}
+	for i := 0; i < count; i++ {
+		result = product(result, items)
+	}
+	return result
+}
+
+func product(left, right []string) []string {
+	var result []string
+	for _, l := range left {
+		for _, r := range right {
+			result = append(result, l+r)
+		}
+	}
+	return limitSamples(result)
+}
+
+func limitSamples(items []string) []string {
+	if len(items) > maxSamples {
+		return items[:maxSamples]
+	}
+	return items
+}
@@ -0,0 +1,27 @@
+package typemapper
+
+import "strings"
+
+// ArraySuffix is suffix of field in bracket array encoding
+//
+//	id[]=1&id[]=2
+const ArraySuffix = "[]"
+
+// ArrayQueryTypeMapper is optional interface of QueryTypeMapper
+// for mappers of lists that accept fields with ArraySuffix,
+// values of such fields are mapped with values of field without suffix
+type ArrayQueryTypeMapper interface {
+	QueryTypeMapper
+
+	AcceptArraySuffix() bool
+}
+
+// TrimArraySuffix return field without ArraySuffix
+// and true if field have it
+func TrimArraySuffix(field string) (string, bool) {
+	if !strings.HasSuffix(field, ArraySuffix) || len(field) == len(ArraySuffix) {
+		return field, false
+	}
+
+	return strings.TrimSuffix(field, ArraySuffix), true
+}
@@ -0,0 +1,56 @@
+package typemapper
+
+import "regexp"
+
+// CapturesTypeMapperFunc is TypeMapperFunc that also take
+// named capture groups of regex key that matched field
+type CapturesTypeMapperFunc func(field string, captures map[string]string, values []string) (interface{}, error)
+
+// CapturesValidateFieldFunc is ValidateFieldFunc that also take
+// named capture groups of regex key that matched field
+type CapturesValidateFieldFunc func(field string, captures map[string]string) error
+
+// WithCaptures convert CapturesTypeMapperFunc to TypeMapperFunc
+// that take captures of regex from field
+func (f CapturesTypeMapperFunc) WithCaptures(regex *regexp.Regexp) TypeMapperFunc {
+	return func(field string, values []string) (interface{}, error) {
+		return f(field, NamedCaptures(regex, field), values)
+	}
+}
+
+// WithCaptures convert CapturesValidateFieldFunc to ValidateFieldFunc
+// that take captures of regex from field
+func (f CapturesValidateFieldFunc) WithCaptures(regex *regexp.Regexp) ValidateFieldFunc {
+	return func(field string) error {
+		return f(field, NamedCaptures(regex, field))
+	}
+}
+
+// NamedCaptures return named capture groups of regex that matched in field
+//
+// Return nil if regex is nil or don't have named groups
+func NamedCaptures(regex *regexp.Regexp, field string) map[string]string {
+	if regex == nil {
+		return nil
+	}
+
+	var (
+		names    = regex.SubexpNames()
+		match    = regex.FindStringSubmatchIndex(field)
+		captures map[string]string
+	)
+
+	for group := 1; group < len(names) && 2*group+1 < len(match); group++ {
+		start, end := match[2*group], match[2*group+1]
+		if names[group] == "" || start < 0 {
+			continue
+		}
+
+		if captures == nil {
+			captures = map[string]string{}
+		}
+		captures[names[group]] = field[start:end]
+	}
+
+	return captures
+}
@@ -0,0 +1,340 @@
+package typemapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TransformFunc change value before it is mapped
+type TransformFunc func(value string) (string, error)
+
+// ResultFunc change mapped value
+type ResultFunc func(field string, value interface{}) (interface{}, error)
+
+var (
+	// TrimSpace is TransformFunc that trim spaces around value
+	TrimSpace TransformFunc = func(value string) (string, error) {
+		return strings.TrimSpace(value), nil
+	}
+
+	// ToLower is TransformFunc that lower case value
+	ToLower TransformFunc = func(value string) (string, error) {
+		return strings.ToLower(value), nil
+	}
+)
+
+// BranchError describe error of one mapper of combinator
+type BranchError struct {
+	// Name of mapper given by Named or position of it
+	Branch string
+
+	Err error
+}
+
+func (b *BranchError) Error() string {
+	return fmt.Sprintf("%s: %v", b.Branch, b.Err)
+}
+
+func (b *BranchError) Unwrap() error {
+	return b.Err
+}
+
+// FirstOfError returned by FirstOf if no mapper succeed
+type FirstOfError []*BranchError
+
+func (f FirstOfError) Error() string {
+	messages := make([]string, len(f))
+	for i, err := range f {
+		messages[i] = err.Error()
+	}
+
+	return "No mapper succeed: " + strings.Join(messages, "; ")
+}
+
+// Is report that error of some mapper match target,
+// it is used by errors.Is
+func (f FirstOfError) Is(target error) bool {
+	for _, err := range f {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As set target to first error of mappers that match it,
+// it is used by errors.As
+func (f FirstOfError) As(target interface{}) bool {
+	for _, err := range f {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type namedMapper struct {
+	QueryTypeMapper
+
+	name string
+}
+
+func (n *namedMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	return MapContext(ctx, n.QueryTypeMapper, field, values)
+}
+
+// Named give name to mapper that is used in errors of combinators
+func Named(name string, mapper QueryTypeMapper) QueryTypeMapper {
+	return &namedMapper{QueryTypeMapper: mapper, name: name}
+}
+
+func branchName(mapper QueryTypeMapper, at int) string {
+	if named, ok := mapper.(*namedMapper); ok {
+		return named.name
+	}
+
+	return fmt.Sprintf("#%d", at)
+}
+
+type transformMapper struct {
+	mapper QueryTypeMapper
+
+	transforms []TransformFunc
+}
+
+// Transform return mapper that change each value by transforms in order
+// before validation and map of mapper
+//
+//	Transform(scalar.Int(), TrimSpace, ToLower)
+func Transform(mapper QueryTypeMapper, transforms ...TransformFunc) QueryTypeMapper {
+	return &transformMapper{mapper: mapper, transforms: transforms}
+}
+
+func (t *transformMapper) transform(values []string) ([]string, error) {
+	transformed := make([]string, len(values))
+	for i, value := range values {
+		for at, transform := range t.transforms {
+			var err error
+			if value, err = transform(value); err != nil {
+				return nil, &BranchError{Branch: fmt.Sprintf("transform #%d", at), Err: err}
+			}
+		}
+		transformed[i] = value
+	}
+
+	return transformed, nil
+}
+
+func (t *transformMapper) ValidateField(field string) error {
+	return t.mapper.ValidateField(field)
+}
+
+func (t *transformMapper) ValidateValues(values []string) error {
+	transformed, err := t.transform(values)
+	if err != nil {
+		return err
+	}
+
+	return t.mapper.ValidateValues(transformed)
+}
+
+func (t *transformMapper) Map(field string, values []string) (interface{}, error) {
+	return t.MapContext(context.Background(), field, values)
+}
+
+func (t *transformMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	transformed, err := t.transform(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapContext(ctx, t.mapper, field, transformed)
+}
+
+type chainMapper struct {
+	mapper QueryTypeMapper
+
+	then []ResultFunc
+}
+
+// Chain return mapper that pass value mapped by mapper
+// through funcs in order, validators of mapper are used
+func Chain(mapper QueryTypeMapper, then ...ResultFunc) QueryTypeMapper {
+	return &chainMapper{mapper: mapper, then: then}
+}
+
+func (c *chainMapper) ValidateField(field string) error {
+	return c.mapper.ValidateField(field)
+}
+
+func (c *chainMapper) ValidateValues(values []string) error {
+	return c.mapper.ValidateValues(values)
+}
+
+func (c *chainMapper) Map(field string, values []string) (interface{}, error) {
+	return c.MapContext(context.Background(), field, values)
+}
+
+func (c *chainMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	result, err := MapContext(ctx, c.mapper, field, values)
+	if err != nil {
+		return nil, &BranchError{Branch: branchName(c.mapper, 0), Err: err}
+	}
+
+	for at, then := range c.then {
+		if result, err = then(field, result); err != nil {
+			return nil, &BranchError{Branch: fmt.Sprintf("#%d", at+1), Err: err}
+		}
+	}
+
+	return result, nil
+}
+
+type firstOfMapper struct {
+	mappers []QueryTypeMapper
+}
+
+// FirstOf return mapper that map by first of mappers
+// which validators pass and which map succeed,
+// field and values are valid if they are valid for one of mappers
+//
+// If no mapper succeed FirstOfError is returned
+//
+//	FirstOf(Named("uuid", scalar.UUIDValue()), Named("slug", slug))
+func FirstOf(mappers ...QueryTypeMapper) QueryTypeMapper {
+	return &firstOfMapper{mappers: mappers}
+}
+
+func (f *firstOfMapper) ValidateField(field string) error {
+	var errs FirstOfError
+	for at, mapper := range f.mappers {
+		err := mapper.ValidateField(field)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, &BranchError{Branch: branchName(mapper, at), Err: err})
+	}
+
+	return errs
+}
+
+func (f *firstOfMapper) ValidateValues(values []string) error {
+	var errs FirstOfError
+	for at, mapper := range f.mappers {
+		err := mapper.ValidateValues(values)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, &BranchError{Branch: branchName(mapper, at), Err: err})
+	}
+
+	return errs
+}
+
+func (f *firstOfMapper) Map(field string, values []string) (interface{}, error) {
+	return f.MapContext(context.Background(), field, values)
+}
+
+func (f *firstOfMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	var errs FirstOfError
+	for at, mapper := range f.mappers {
+		err := validate(mapper, field, values)
+		if err == nil {
+			var result interface{}
+			if result, err = MapContext(ctx, mapper, field, values); err == nil {
+				return result, nil
+			}
+		}
+		errs = append(errs, &BranchError{Branch: branchName(mapper, at), Err: err})
+	}
+
+	return nil, errs
+}
+
+type optionalMapper struct {
+	mapper QueryTypeMapper
+}
+
+// Optional return mapper that map no values or only empty values to nil
+// and other values by mapper
+func Optional(mapper QueryTypeMapper) QueryTypeMapper {
+	return &optionalMapper{mapper: mapper}
+}
+
+func isEmpty(values []string) bool {
+	for _, value := range values {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *optionalMapper) ValidateField(field string) error {
+	return o.mapper.ValidateField(field)
+}
+
+func (o *optionalMapper) ValidateValues(values []string) error {
+	if isEmpty(values) {
+		return nil
+	}
+
+	return o.mapper.ValidateValues(values)
+}
+
+func (o *optionalMapper) Map(field string, values []string) (interface{}, error) {
+	return o.MapContext(context.Background(), field, values)
+}
+
+func (o *optionalMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	if isEmpty(values) {
+		return nil, nil
+	}
+
+	return MapContext(ctx, o.mapper, field, values)
+}
+
+type forEachMapper struct {
+	mapper QueryTypeMapper
+}
+
+// ForEach return mapper that map each value by mapper separately
+// to []interface{}
+func ForEach(mapper QueryTypeMapper) QueryTypeMapper {
+	return &forEachMapper{mapper: mapper}
+}
+
+func (f *forEachMapper) ValidateField(field string) error {
+	return f.mapper.ValidateField(field)
+}
+
+func (f *forEachMapper) ValidateValues(values []string) error {
+	for at, value := range values {
+		if err := f.mapper.ValidateValues([]string{value}); err != nil {
+			return &BranchError{Branch: fmt.Sprintf("value #%d %q", at, value), Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (f *forEachMapper) Map(field string, values []string) (interface{}, error) {
+	return f.MapContext(context.Background(), field, values)
+}
+
+func (f *forEachMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	result := make([]interface{}, len(values))
+	for at, value := range values {
+		mapped, err := MapContext(ctx, f.mapper, field, []string{value})
+		if err != nil {
+			return nil, &BranchError{Branch: fmt.Sprintf("value #%d %q", at, value), Err: err}
+		}
+		result[at] = mapped
+	}
+
+	return result, nil
+}
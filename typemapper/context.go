@@ -0,0 +1,43 @@
+package typemapper
+
+import "context"
+
+// ContextTypeMapperFunc is TypeMapperFunc that take context of parse
+type ContextTypeMapperFunc func(ctx context.Context, field string, values []string) (interface{}, error)
+
+// ContextQueryTypeMapper is optional interface of QueryTypeMapper
+// to map values with context of parse
+type ContextQueryTypeMapper interface {
+	MapContext(ctx context.Context, field string, values []string) (interface{}, error)
+}
+
+// ToContext adapt TypeMapperFunc to ContextTypeMapperFunc that ignore context
+func (f TypeMapperFunc) ToContext() ContextTypeMapperFunc {
+	return func(ctx context.Context, field string, values []string) (interface{}, error) {
+		return f(field, values)
+	}
+}
+
+// MapContext map values with MapContext of typeMapper
+// if it implement ContextQueryTypeMapper and with Map otherwise
+func MapContext(ctx context.Context, typeMapper QueryTypeMapper, field string, values []string) (interface{}, error) {
+	if contextMapper, ok := typeMapper.(ContextQueryTypeMapper); ok {
+		return contextMapper.MapContext(ctx, field, values)
+	}
+
+	return typeMapper.Map(field, values)
+}
+
+type capturesKey struct{}
+
+// ContextWithCaptures return context that keep named captures of regex key
+func ContextWithCaptures(ctx context.Context, captures map[string]string) context.Context {
+	return context.WithValue(ctx, capturesKey{}, captures)
+}
+
+// CapturesFromContext return named captures of regex key
+// that matched field, nil if context don't have them
+func CapturesFromContext(ctx context.Context) map[string]string {
+	captures, _ := ctx.Value(capturesKey{}).(map[string]string)
+	return captures
+}
@@ -0,0 +1,288 @@
+// Package datemath provide typemapper.QueryTypeMapper of time
+// that accept absolute time and date math expressions
+// like in Elasticsearch
+//
+//	now-7d/d
+//	today+1h
+//	startOfMonth-1M
+//	2021-01-01||+1M/d
+//
+// Expression is anchor followed by operations:
+//
+//	+N unit  add N units
+//	-N unit  subtract N units
+//	/unit    round down to unit
+//
+// where unit is one of y, M, w, d, h, H, m, s.
+// Operations that overflow or give year out of 1..9999 are rejected
+package datemath
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+)
+
+// BadExpression returned if date math expression can't be parsed
+var BadExpression = errors.New("Bad date math expression")
+
+// anchors that can start expression, computed from now in location,
+// none of names is prefix of other
+var anchors = []struct {
+	name  string
+	round func(now time.Time) time.Time
+}{
+	{"now", func(now time.Time) time.Time { return now }},
+	{"today", func(now time.Time) time.Time { return round(now, 'd') }},
+	{"yesterday", func(now time.Time) time.Time { return round(now, 'd').AddDate(0, 0, -1) }},
+	{"tomorrow", func(now time.Time) time.Time { return round(now, 'd').AddDate(0, 0, 1) }},
+	{"startOfWeek", func(now time.Time) time.Time { return round(now, 'w') }},
+	{"startOfMonth", func(now time.Time) time.Time { return round(now, 'M') }},
+	{"startOfYear", func(now time.Time) time.Time { return round(now, 'y') }},
+}
+
+// DateMathMapper map value to time.Time
+type DateMathMapper struct {
+	// mapper of absolute time
+	time *scalar.TimeMapper
+
+	clock func() time.Time
+
+	location *time.Location
+}
+
+// Time return mapper of absolute time or date math expression,
+// absolute time is parsed by scalar.TimeMapper
+// with layouts RFC3339 and DateOnly
+func Time() *DateMathMapper {
+	return &DateMathMapper{
+		time:     scalar.Time().SetLayouts(scalar.RFC3339, scalar.DateOnly),
+		clock:    time.Now,
+		location: time.UTC,
+	}
+}
+
+// SetClock set func that return now, default is time.Now
+func (d *DateMathMapper) SetClock(clock func() time.Time) *DateMathMapper {
+	d.clock = clock
+	return d
+}
+
+// SetLocation set location of now, rounding and absolute time without zone,
+// default is UTC
+func (d *DateMathMapper) SetLocation(location *time.Location) *DateMathMapper {
+	d.location = location
+	d.time.SetLocation(location)
+	return d
+}
+
+// SetLayouts set layouts of absolute time, see scalar.TimeMapper.SetLayouts
+func (d *DateMathMapper) SetLayouts(layouts ...string) *DateMathMapper {
+	d.time.SetLayouts(layouts...)
+	return d
+}
+
+// SetEmpty set how empty string is mapped, default is scalar.EmptyReject
+func (d *DateMathMapper) SetEmpty(empty scalar.Empty) *DateMathMapper {
+	d.time.SetEmpty(empty)
+	return d
+}
+
+// Can validate values count or type if needed(string, json, or users type)
+func (d *DateMathMapper) ValidateValues(values []string) error {
+	return d.time.ValidateValues(values)
+}
+
+// Can validateField format
+func (d *DateMathMapper) ValidateField(field string) error {
+	return d.time.ValidateField(field)
+}
+
+func (d *DateMathMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := scalar.OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return d.time.Map(field, values)
+	}
+
+	return d.Parse(value)
+}
+
+// Parse return time of absolute time or date math expression
+func (d *DateMathMapper) Parse(value string) (time.Time, error) {
+	anchor, expression, err := d.anchor(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	result, err := apply(anchor, expression)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q: %v", BadExpression, value, err)
+	}
+
+	return result, nil
+}
+
+// anchor return time of expression start and rest of expression
+func (d *DateMathMapper) anchor(value string) (time.Time, string, error) {
+	if at := strings.Index(value, "||"); at >= 0 {
+		anchor, err := d.absolute(value[:at])
+		return anchor, value[at+2:], err
+	}
+
+	for _, a := range anchors {
+		if strings.HasPrefix(value, a.name) {
+			return a.round(d.clock().In(d.location)), value[len(a.name):], nil
+		}
+	}
+
+	anchor, err := d.absolute(value)
+	return anchor, "", err
+}
+
+func (d *DateMathMapper) absolute(value string) (time.Time, error) {
+	result, err := d.time.Map("", []string{value})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return result.(time.Time), nil
+}
+
+// apply apply operations of expression to t
+func apply(t time.Time, expression string) (time.Time, error) {
+	for expression != "" {
+		op := expression[0]
+		expression = expression[1:]
+
+		switch op {
+		case '/':
+			if expression == "" || !isUnit(expression[0]) {
+				return t, fmt.Errorf("expect unit after /")
+			}
+			t = round(t, expression[0])
+			expression = expression[1:]
+		case '+', '-':
+			digits := len(expression) - len(strings.TrimLeft(expression, "0123456789"))
+			if digits == 0 {
+				return t, fmt.Errorf("expect number after %c", op)
+			}
+
+			n, err := strconv.Atoi(expression[:digits])
+			if err != nil {
+				return t, err
+			}
+
+			expression = expression[digits:]
+			if expression == "" || !isUnit(expression[0]) {
+				return t, fmt.Errorf("expect unit after %c%d", op, n)
+			}
+
+			if op == '-' {
+				n = -n
+			}
+			if t, err = add(t, n, expression[0]); err != nil {
+				return t, err
+			}
+			expression = expression[1:]
+		default:
+			return t, fmt.Errorf("unexpected %q", op)
+		}
+	}
+
+	return t, nil
+}
+
+func isUnit(unit byte) bool {
+	return strings.IndexByte("yMwdhHms", unit) >= 0
+}
+
+// maxYears limit years that can be added by one operation
+const maxYears = 10000
+
+// add add n units to t, n and result should be in sane range,
+// so result don't overflow
+func add(t time.Time, n int, unit byte) (time.Time, error) {
+	var (
+		limit    int64
+		duration time.Duration
+	)
+	switch unit {
+	case 'y':
+		limit = maxYears
+	case 'M':
+		limit = 12 * maxYears
+	case 'w':
+		limit = 53 * maxYears
+	case 'd':
+		limit = 366 * maxYears
+	case 'h', 'H':
+		duration = time.Hour
+	case 'm':
+		duration = time.Minute
+	default:
+		duration = time.Second
+	}
+
+	if duration != 0 {
+		limit = math.MaxInt64 / int64(duration)
+	}
+
+	if int64(n) > limit || int64(n) < -limit {
+		return t, fmt.Errorf("%d%c is out of range", n, unit)
+	}
+
+	switch unit {
+	case 'y':
+		t = t.AddDate(n, 0, 0)
+	case 'M':
+		t = t.AddDate(0, n, 0)
+	case 'w':
+		t = t.AddDate(0, 0, 7*n)
+	case 'd':
+		t = t.AddDate(0, 0, n)
+	default:
+		t = t.Add(time.Duration(n) * duration)
+	}
+
+	if year := t.Year(); year < 1 || year > 9999 {
+		return t, fmt.Errorf("year %d is out of range", year)
+	}
+
+	return t, nil
+}
+
+// round round t down to unit in location of t, weeks start on monday
+func round(t time.Time, unit byte) time.Time {
+	var (
+		year, month, day = t.Date()
+		hour, min, sec   = t.Clock()
+		location         = t.Location()
+	)
+
+	switch unit {
+	case 'y':
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+	case 'M':
+		return time.Date(year, month, 1, 0, 0, 0, 0, location)
+	case 'w':
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, location)
+	case 'd':
+		return time.Date(year, month, day, 0, 0, 0, 0, location)
+	case 'h', 'H':
+		return time.Date(year, month, day, hour, 0, 0, 0, location)
+	case 'm':
+		return time.Date(year, month, day, hour, min, 0, 0, location)
+	}
+
+	return time.Date(year, month, day, hour, min, sec, 0, location)
+}
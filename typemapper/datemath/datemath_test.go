@@ -0,0 +1,119 @@
+package datemath_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0B1t322/QueryParser/typemapper/datemath"
+	"github.com/0B1t322/QueryParser/typemapper/ranges"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunc_DateMath(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	// wednesday
+	now := time.Date(2021, 5, 12, 22, 30, 15, 0, time.UTC)
+	mapper := datemath.Time().
+		SetClock(func() time.Time { return now }).
+		SetLocation(moscow)
+
+	t.Run(
+		"Expressions",
+		func(t *testing.T) {
+			for value, expect := range map[string]time.Time{
+				"now":                  now,
+				"now-7d":               now.AddDate(0, 0, -7),
+				"now-30d/d":            time.Date(2021, 4, 13, 0, 0, 0, 0, moscow),
+				"now/d":                time.Date(2021, 5, 13, 0, 0, 0, 0, moscow),
+				"now+1h/h":             time.Date(2021, 5, 13, 2, 0, 0, 0, moscow),
+				"now/w":                time.Date(2021, 5, 10, 0, 0, 0, 0, moscow),
+				"now-1M/M":             time.Date(2021, 4, 1, 0, 0, 0, 0, moscow),
+				"now/y+1y":             time.Date(2022, 1, 1, 0, 0, 0, 0, moscow),
+				"now-90m/m":            time.Date(2021, 5, 13, 0, 0, 0, 0, moscow),
+				"now+15s/s":            time.Date(2021, 5, 13, 1, 30, 30, 0, moscow),
+				"today":                time.Date(2021, 5, 13, 0, 0, 0, 0, moscow),
+				"yesterday":            time.Date(2021, 5, 12, 0, 0, 0, 0, moscow),
+				"tomorrow-1h":          time.Date(2021, 5, 13, 23, 0, 0, 0, moscow),
+				"startOfWeek":          time.Date(2021, 5, 10, 0, 0, 0, 0, moscow),
+				"startOfMonth":         time.Date(2021, 5, 1, 0, 0, 0, 0, moscow),
+				"startOfYear":          time.Date(2021, 1, 1, 0, 0, 0, 0, moscow),
+				"2021-01-31||+1M/d":    time.Date(2021, 3, 3, 0, 0, 0, 0, moscow),
+				"2021-01-01":           time.Date(2021, 1, 1, 0, 0, 0, 0, moscow),
+				"2021-01-01T10:00:00Z": time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC),
+			} {
+				result, err := mapper.Map("created", []string{value})
+				require.NoError(t, err, value)
+				require.True(t, expect.Equal(result.(time.Time)), "%s: expect %v, got %v", value, expect, result)
+			}
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			for value, expect := range map[string]error{
+				"now-":           datemath.BadExpression,
+				"now-7":          datemath.BadExpression,
+				"now-7x":         datemath.BadExpression,
+				"now/":           datemath.BadExpression,
+				"now*2d":         datemath.BadExpression,
+				"last week":      scalar.BadSyntax,
+				"2021-13-01||/d": scalar.BadSyntax,
+			} {
+				_, err := mapper.Map("created", []string{value})
+				require.True(t, errors.Is(err, expect), "%s: %v", value, err)
+			}
+
+			_, err := mapper.Map("created", []string{""})
+			require.True(t, errors.Is(err, scalar.EmptyValue))
+			require.True(t, errors.Is(mapper.ValidateValues([]string{"now", "today"}), scalar.ExpectOneValue))
+			_, mapErr := mapper.Map("field", nil)
+			require.True(t, errors.Is(mapErr, scalar.ExpectOneValue))
+		},
+	)
+
+	t.Run(
+		"Overflow",
+		func(t *testing.T) {
+			for _, value := range []string{
+				"now+3000000h",
+				"now-3000000h",
+				"now+9999999999d",
+				"now+9999999999999m",
+				"now+99999999999999999999s",
+				"now+10001y",
+				"now+9000y",
+				"now+5000y+5000y",
+				"0001-01-01||-1d",
+			} {
+				_, err := mapper.Map("created", []string{value})
+				require.True(t, errors.Is(err, datemath.BadExpression), "%s: %v", value, err)
+			}
+
+			result, err := mapper.Map("created", []string{"now+2000000h"})
+			require.NoError(t, err)
+			require.Equal(t, 2249, result.(time.Time).Year())
+		},
+	)
+
+	t.Run(
+		"Range",
+		func(t *testing.T) {
+			result, err := ranges.Of(mapper).Map("created", []string{"now-7d/d..now"})
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				ranges.Range{
+					Min:          time.Date(2021, 5, 6, 0, 0, 0, 0, moscow),
+					Max:          now.In(moscow),
+					MinInclusive: true,
+					MaxInclusive: true,
+				},
+				result,
+			)
+		},
+	)
+}
@@ -0,0 +1,172 @@
+// Package decimal provide typemapper.QueryTypeMapper of exact decimal numbers
+// and money amounts without float rounding
+//
+//	price[lte]=19.99
+//	price[lte]=19.99EUR
+package decimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0B1t322/QueryParser/typemapper/ranges"
+)
+
+var (
+	// BadDecimal returned if value is not decimal number
+	BadDecimal = errors.New("Bad decimal")
+
+	// TooManyDigits returned if value exceed scale or precision limits
+	TooManyDigits = errors.New("Too many digits")
+
+	// BadCurrency returned if currency is missing or not allowed
+	BadCurrency = errors.New("Bad currency")
+)
+
+// Decimal is exact number Unscaled * 10^-Scale
+type Decimal struct {
+	Unscaled *big.Int
+
+	// digits after point
+	Scale int
+}
+
+// Parse parse decimal number like 19.99 or -0.5,
+// exponent is not supported
+func Parse(value string) (Decimal, error) {
+	digits := strings.TrimLeft(value, "+-")
+	if len(value)-len(digits) > 1 {
+		return Decimal{}, fmt.Errorf("%w: %q", BadDecimal, value)
+	}
+
+	integer, fraction := digits, ""
+	if at := strings.IndexByte(digits, '.'); at >= 0 {
+		integer, fraction = digits[:at], digits[at+1:]
+		if fraction == "" {
+			return Decimal{}, fmt.Errorf("%w: %q", BadDecimal, value)
+		}
+	}
+
+	if integer == "" || !isDigits(integer) || !isDigits(fraction) {
+		return Decimal{}, fmt.Errorf("%w: %q", BadDecimal, value)
+	}
+
+	unscaled, _ := new(big.Int).SetString(integer+fraction, 10)
+	if strings.HasPrefix(value, "-") {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{Unscaled: unscaled, Scale: len(fraction)}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Precision return count of digits before point without leading zeros
+// plus Scale like precision of SQL NUMERIC
+func (d Decimal) Precision() int {
+	integer := new(big.Int).Abs(d.Unscaled)
+	integer.Quo(integer, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.Scale)), nil))
+
+	if integer.Sign() == 0 {
+		return maxInt(d.Scale, 1)
+	}
+
+	return len(integer.String()) + d.Scale
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Rat return exact value of d
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(
+		d.Unscaled,
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.Scale)), nil),
+	)
+}
+
+// Cmp return -1, 0 or 1 if d is lower, equal or greater than other
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+// String return digits of d with point
+func (d Decimal) String() string {
+	if d.Unscaled == nil {
+		return "0"
+	}
+
+	digits := new(big.Int).Abs(d.Unscaled).String()
+	if d.Scale > 0 {
+		if len(digits) <= d.Scale {
+			digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.Scale] + "." + digits[len(digits)-d.Scale:]
+	}
+
+	if d.Unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Value implement driver.Valuer, value is passed as string
+// that database drivers convert to NUMERIC without rounding
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Money is decimal amount in currency
+type Money struct {
+	Amount Decimal
+
+	// Currency code like EUR
+	Currency string
+}
+
+// String return amount with currency
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// Value implement driver.Valuer, value is amount as string
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount.Value()
+}
+
+// Compare compare Decimal or Money values,
+// it can be used as ranges.CompareFunc
+func Compare(a, b interface{}) (int, error) {
+	switch a := a.(type) {
+	case Decimal:
+		if b, ok := b.(Decimal); ok {
+			return a.Cmp(b), nil
+		}
+	case Money:
+		if b, ok := b.(Money); ok && a.Currency == b.Currency {
+			return a.Amount.Cmp(b.Amount), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: can't compare %v and %v", ranges.UnsupportedType, a, b)
+}
@@ -0,0 +1,114 @@
+package decimal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0B1t322/QueryParser/typemapper/decimal"
+	"github.com/0B1t322/QueryParser/typemapper/ranges"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunc_Decimal(t *testing.T) {
+	t.Run(
+		"Parse",
+		func(t *testing.T) {
+			for value, expect := range map[string]string{
+				"19.99":  "19.99",
+				"-0.05":  "-0.05",
+				"+10":    "10",
+				"007.50": "7.50",
+				"0.000":  "0.000",
+			} {
+				d, err := decimal.Parse(value)
+				require.NoError(t, err, value)
+				require.Equal(t, expect, d.String(), value)
+			}
+
+			for _, value := range []string{"", "1.", ".5", "1e3", "--1", "1,5", "NaN"} {
+				_, err := decimal.Parse(value)
+				require.True(t, errors.Is(err, decimal.BadDecimal), value)
+			}
+		},
+	)
+
+	t.Run(
+		"Exact",
+		func(t *testing.T) {
+			d, err := decimal.Parse("19.99")
+			require.NoError(t, err)
+			require.Equal(t, int64(1999), d.Unscaled.Int64())
+			require.Equal(t, 2, d.Scale)
+			require.Equal(t, 4, d.Precision())
+
+			value, err := d.Value()
+			require.NoError(t, err)
+			require.Equal(t, "19.99", value)
+
+			other, _ := decimal.Parse("19.990")
+			require.Equal(t, 0, d.Cmp(other))
+		},
+	)
+
+	t.Run(
+		"Limits",
+		func(t *testing.T) {
+			mapper := decimal.New().SetMaxScale(2).SetMaxPrecision(5).SetAllowNegative(false)
+
+			result, err := mapper.Map("price", []string{"999.99"})
+			require.NoError(t, err)
+			require.Equal(t, "999.99", result.(decimal.Decimal).String())
+
+			_, err = mapper.Map("price", []string{"0.001"})
+			require.True(t, errors.Is(err, decimal.TooManyDigits))
+
+			_, err = mapper.Map("price", []string{"1000.00"})
+			require.True(t, errors.Is(err, decimal.TooManyDigits))
+
+			_, err = mapper.Map("price", []string{"-1"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+
+			require.True(t, errors.Is(mapper.ValidateValues([]string{"1", "2"}), scalar.ExpectOneValue))
+			_, mapErr := mapper.Map("field", nil)
+			require.True(t, errors.Is(mapErr, scalar.ExpectOneValue))
+		},
+	)
+
+	t.Run(
+		"Currency",
+		func(t *testing.T) {
+			mapper := decimal.New().SetCurrencies("EUR", "USD")
+
+			for _, value := range []string{"19.99EUR", "19.99 EUR"} {
+				result, err := mapper.Map("price", []string{value})
+				require.NoError(t, err, value)
+				require.Equal(t, "19.99 EUR", result.(decimal.Money).String(), value)
+			}
+
+			for _, value := range []string{"19.99", "19.99RUB"} {
+				_, err := mapper.Map("price", []string{value})
+				require.True(t, errors.Is(err, decimal.BadCurrency), value)
+			}
+		},
+	)
+
+	t.Run(
+		"Range",
+		func(t *testing.T) {
+			mapper := ranges.Of(decimal.New()).SetCompare(decimal.Compare)
+
+			_, err := mapper.Map("price", []string{"10.5..10.49"})
+			require.True(t, errors.Is(err, ranges.InvertedRange))
+
+			result, err := mapper.Map("price", []string{"10.49..10.5"})
+			require.NoError(t, err)
+			require.Equal(t, "[10.49,10.5]", result.(ranges.Range).String())
+
+			_, err = ranges.Of(decimal.New().SetCurrencies("EUR", "USD")).
+				SetCompare(decimal.Compare).
+				Map("price", []string{"1EUR..2USD"})
+			require.True(t, errors.Is(err, ranges.UnsupportedType))
+		},
+	)
+}
@@ -0,0 +1,130 @@
+package decimal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+)
+
+// DecimalMapper map value to Decimal or to Money if currencies are set
+type DecimalMapper struct {
+	maxScale, maxPrecision *int
+
+	negative bool
+
+	currencies []string
+}
+
+// New return mapper of Decimal value
+func New() *DecimalMapper {
+	return &DecimalMapper{negative: true}
+}
+
+// SetMaxScale set maximal count of digits after point
+func (d *DecimalMapper) SetMaxScale(scale int) *DecimalMapper {
+	d.maxScale = &scale
+	return d
+}
+
+// SetMaxPrecision set maximal count of significant digits,
+// see Decimal.Precision
+func (d *DecimalMapper) SetMaxPrecision(precision int) *DecimalMapper {
+	d.maxPrecision = &precision
+	return d
+}
+
+// SetAllowNegative set if negative values are allowed, default is true
+func (d *DecimalMapper) SetAllowNegative(allow bool) *DecimalMapper {
+	d.negative = allow
+	return d
+}
+
+// SetCurrencies make mapper require one of currencies
+// after amount and return Money
+//
+//	19.99EUR
+//	19.99 EUR
+func (d *DecimalMapper) SetCurrencies(currencies ...string) *DecimalMapper {
+	d.currencies = currencies
+	return d
+}
+
+// Can validate values count or type if needed(string, json, or users type)
+func (d *DecimalMapper) ValidateValues(values []string) error {
+	_, err := scalar.OneValue(values)
+	return err
+}
+
+// Can validateField format
+func (d *DecimalMapper) ValidateField(field string) error {
+	return nil
+}
+
+func (d *DecimalMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := scalar.OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, scalar.EmptyValue
+	}
+
+	if d.currencies == nil {
+		return d.parse(value)
+	}
+
+	amount, currency := splitCurrency(value)
+	if !d.allowed(currency) {
+		return nil, fmt.Errorf("%w in %q, expect one of %s", BadCurrency, value, strings.Join(d.currencies, ", "))
+	}
+
+	decimal, err := d.parse(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return Money{Amount: decimal, Currency: currency}, nil
+}
+
+func (d *DecimalMapper) parse(value string) (Decimal, error) {
+	decimal, err := Parse(value)
+	if err != nil {
+		return decimal, err
+	}
+
+	if !d.negative && decimal.Unscaled.Sign() < 0 {
+		return decimal, fmt.Errorf("%w: %s is negative", scalar.OutOfRange, value)
+	}
+
+	if d.maxScale != nil && decimal.Scale > *d.maxScale {
+		return decimal, fmt.Errorf("%w: %s have more than %d digits after point", TooManyDigits, value, *d.maxScale)
+	}
+
+	if d.maxPrecision != nil && decimal.Precision() > *d.maxPrecision {
+		return decimal, fmt.Errorf("%w: %s have more than %d digits", TooManyDigits, value, *d.maxPrecision)
+	}
+
+	return decimal, nil
+}
+
+func (d *DecimalMapper) allowed(currency string) bool {
+	for _, c := range d.currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCurrency split value to amount and trailing letters
+func splitCurrency(value string) (amount, currency string) {
+	at := len(value)
+	for at > 0 && value[at-1] >= 'A' && value[at-1] <= 'Z' {
+		at--
+	}
+
+	return strings.TrimSpace(value[:at]), value[at:]
+}
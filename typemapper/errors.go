@@ -0,0 +1,64 @@
+package typemapper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// InvalidField matches errors returned by QueryTypeFactory
+	// when QueryTypeMapper.ValidateField fails
+	InvalidField = errors.New("Invalid field")
+
+	// InvalidValues matches errors returned by QueryTypeFactory
+	// when QueryTypeMapper.ValidateValues fails
+	InvalidValues = errors.New("Invalid values")
+
+	// AmbiguousField matches AmbiguousFieldError
+	AmbiguousField = errors.New("Ambiguous field")
+)
+
+// AmbiguousFieldError returned when field match
+// more than one regex key and ambiguity is rejected
+type AmbiguousFieldError struct {
+	Field string
+
+	// Keys that match field in order of matching
+	Keys []string
+}
+
+func (a *AmbiguousFieldError) Error() string {
+	return fmt.Sprintf("Field %q is ambiguous, it match keys: %s", a.Field, strings.Join(a.Keys, ", "))
+}
+
+func (a *AmbiguousFieldError) Is(target error) bool {
+	return target == AmbiguousField
+}
+
+// validateError keep original error of validation
+// but can be matched with errors.Is by kind
+type validateError struct {
+	kind error
+	err  error
+}
+
+func (v *validateError) Error() string {
+	return v.err.Error()
+}
+
+func (v *validateError) Unwrap() error {
+	return v.err
+}
+
+func (v *validateError) Is(target error) bool {
+	return target == v.kind
+}
+
+func fieldError(err error) error {
+	return &validateError{kind: InvalidField, err: err}
+}
+
+func valuesError(err error) error {
+	return &validateError{kind: InvalidValues, err: err}
+}
@@ -1,5 +1,7 @@
 package typemapper
 
+import "context"
+
 func (q *QueryTypeFactory) ToQueryTypeMapper() QueryTypeMapper {
 	var queryMapper *queryTypeFactoryToQueryTypeMapper = (*queryTypeFactoryToQueryTypeMapper)(q)
 
@@ -25,13 +27,17 @@ func (q *queryTypeFactoryToQueryTypeMapper) ValidateField(field string) error {
 }
 
 func (q *queryTypeFactoryToQueryTypeMapper) Map(field string, values []string) (interface{}, error) {
+	return q.MapContext(context.Background(), field, values)
+}
+
+func (q *queryTypeFactoryToQueryTypeMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
 	var factory *QueryTypeFactory = (*QueryTypeFactory)(q)
 	{
 		if err := factory.Validate(field, values); err != nil {
 			return nil, err
 		}
 
-		return factory.MapField(field, values)
+		return factory.MapFieldContext(ctx, field, values)
 	}
 }
 
@@ -49,12 +55,16 @@ func (q *queryTypeFactoryToReqexQueryTypeMapper) ValidateField(field string) err
 }
 
 func (q *queryTypeFactoryToReqexQueryTypeMapper) Map(field string, values []string) (interface{}, error) {
+	return q.MapContext(context.Background(), field, values)
+}
+
+func (q *queryTypeFactoryToReqexQueryTypeMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
 	var factory *QueryTypeFactory = (*QueryTypeFactory)(q)
 	{
 		if err := factory.ValidateRegexField(field, values); err != nil {
 			return nil, err
 		}
 
-		return factory.MapRegexField(field, values)
+		return factory.MapRegexFieldContext(ctx, field, values)
 	}
 }
\ No newline at end of file
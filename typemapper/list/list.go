@@ -0,0 +1,248 @@
+// Package list provide typemapper.QueryTypeMapper of lists
+// that map each item with element mapper
+//
+// List can be encoded by repeated keys, separated values or bracket array:
+//
+//	id=1&id=2
+//	id=1,2
+//	id[]=1&id[]=2
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+)
+
+var (
+	// TooFewItems returned if list have less items than SetMinItems
+	TooFewItems = errors.New("Too few items")
+
+	// TooManyItems returned if list have more items than SetMaxItems
+	TooManyItems = errors.New("Too many items")
+
+	// RepeatedNotAllowed returned if values are repeated
+	// but Repeated encoding is not enabled
+	RepeatedNotAllowed = errors.New("Repeated values not allowed")
+
+	// UnterminatedQuote returned if quoted item is not closed
+	UnterminatedQuote = errors.New("Unterminated quote")
+)
+
+// Encoding of list in query, can be combined
+type Encoding int
+
+const (
+	// Repeated encoding: id=1&id=2
+	Repeated Encoding = 1 << iota
+
+	// Separated encoding: id=1,2
+	Separated
+
+	// Brackets encoding: id[]=1&id[]=2
+	Brackets
+
+	// AllEncodings enable all encodings
+	AllEncodings = Repeated | Separated | Brackets
+)
+
+// ItemError describe error of list item
+type ItemError struct {
+	Index int
+	Value string
+	Err   error
+}
+
+func (i *ItemError) Error() string {
+	return fmt.Sprintf("Item %d %q: %v", i.Index, i.Value, i.Err)
+}
+
+func (i *ItemError) Unwrap() error {
+	return i.Err
+}
+
+// ListMapper map values to []interface{} with element mapper
+type ListMapper struct {
+	element typemapper.QueryTypeMapper
+
+	encodings Encoding
+
+	separator, quote, escape rune
+
+	minItems, maxItems *int
+}
+
+// Of return mapper of list which items are mapped by element,
+// by default all encodings are enabled, separator is comma,
+// quote is double quote and escape is backslash
+//
+// Element get each item as single value
+func Of(element typemapper.QueryTypeMapper) *ListMapper {
+	return &ListMapper{
+		element:   element,
+		encodings: AllEncodings,
+		separator: ',',
+		quote:     '"',
+		escape:    '\\',
+	}
+}
+
+// SetEncodings set enabled encodings
+func (l *ListMapper) SetEncodings(encodings Encoding) *ListMapper {
+	l.encodings = encodings
+	return l
+}
+
+// SetSeparator set separator of Separated encoding
+func (l *ListMapper) SetSeparator(separator rune) *ListMapper {
+	l.separator = separator
+	return l
+}
+
+// SetQuote set quote of items that contain separator, 0 disable quoting
+func (l *ListMapper) SetQuote(quote rune) *ListMapper {
+	l.quote = quote
+	return l
+}
+
+// SetEscape set escape of separator and quote, 0 disable escaping
+func (l *ListMapper) SetEscape(escape rune) *ListMapper {
+	l.escape = escape
+	return l
+}
+
+// SetMinItems set minimal count of items
+func (l *ListMapper) SetMinItems(min int) *ListMapper {
+	l.minItems = &min
+	return l
+}
+
+// SetMaxItems set maximal count of items
+func (l *ListMapper) SetMaxItems(max int) *ListMapper {
+	l.maxItems = &max
+	return l
+}
+
+// Implement typemapper.ArrayQueryTypeMapper interface
+func (l *ListMapper) AcceptArraySuffix() bool {
+	return l.encodings&Brackets != 0
+}
+
+// Can validateField format
+func (l *ListMapper) ValidateField(field string) error {
+	return l.element.ValidateField(field)
+}
+
+// Can validate values count or type if needed(string, json, or users type)
+func (l *ListMapper) ValidateValues(values []string) error {
+	items, err := l.Items(values)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		if err := l.element.ValidateValues([]string{item}); err != nil {
+			return &ItemError{Index: i, Value: item, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (l *ListMapper) Map(field string, values []string) (interface{}, error) {
+	return l.MapContext(context.Background(), field, values)
+}
+
+// Implement typemapper.ContextQueryTypeMapper interface
+func (l *ListMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	items, err := l.Items(values)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		value, err := typemapper.MapContext(ctx, l.element, field, []string{item})
+		if err != nil {
+			return nil, &ItemError{Index: i, Value: item, Err: err}
+		}
+		result[i] = value
+	}
+
+	return result, nil
+}
+
+// Items return items of values according to encodings
+// and check count of items
+func (l *ListMapper) Items(values []string) ([]string, error) {
+	if len(values) > 1 && l.encodings&(Repeated|Brackets) == 0 {
+		return nil, fmt.Errorf("%w, got %d values", RepeatedNotAllowed, len(values))
+	}
+
+	var items []string
+	if l.encodings&Separated == 0 {
+		items = values
+	} else {
+		for _, value := range values {
+			split, err := l.split(value)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, split...)
+		}
+	}
+
+	if l.minItems != nil && len(items) < *l.minItems {
+		return nil, fmt.Errorf("%w: got %d, min %d", TooFewItems, len(items), *l.minItems)
+	}
+
+	if l.maxItems != nil && len(items) > *l.maxItems {
+		return nil, fmt.Errorf("%w: got %d, max %d", TooManyItems, len(items), *l.maxItems)
+	}
+
+	return items, nil
+}
+
+// split split value by separator with respect of quotes and escapes,
+// empty value have no items
+func (l *ListMapper) split(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var (
+		items   []string
+		item    []rune
+		quoted  bool
+		escaped bool
+	)
+
+	for _, r := range value {
+		switch {
+		case escaped:
+			item = append(item, r)
+			escaped = false
+		case l.escape != 0 && r == l.escape:
+			escaped = true
+		case l.quote != 0 && r == l.quote:
+			quoted = !quoted
+		case r == l.separator && !quoted:
+			items = append(items, string(item))
+			item = item[:0]
+		default:
+			item = append(item, r)
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("%w in %q", UnterminatedQuote, value)
+	}
+
+	if escaped {
+		item = append(item, l.escape)
+	}
+
+	return append(items, string(item)), nil
+}
@@ -0,0 +1,99 @@
+package list_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/list"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunc_List(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run(
+		"Encodings",
+		func(t *testing.T) {
+			mapper := list.Of(scalar.Int())
+
+			value, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1", "2"})
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{1, 2}, value)
+
+			value, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1,2", "3"})
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{1, 2, 3}, value)
+
+			value, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{""})
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{}, value)
+		},
+	)
+
+	t.Run(
+		"RepeatedNotAllowed",
+		func(t *testing.T) {
+			mapper := list.Of(scalar.Int()).SetEncodings(list.Separated)
+			require.False(t, mapper.AcceptArraySuffix())
+
+			_, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1", "2"})
+			require.True(t, errors.Is(err, list.RepeatedNotAllowed))
+		},
+	)
+
+	t.Run(
+		"NotSeparated",
+		func(t *testing.T) {
+			value, err := typemapper.ValidateAndMap(ctx, list.Of(scalar.String()).SetEncodings(list.Repeated), "field", []string{"a,b", "c"})
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{"a,b", "c"}, value)
+		},
+	)
+
+	t.Run(
+		"QuoteAndEscape",
+		func(t *testing.T) {
+			mapper := list.Of(scalar.String().SetEmpty(scalar.EmptyAsZero))
+
+			value, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{`"a,b",c\,d,"e\"f",`})
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{"a,b", "c,d", `e"f`, ""}, value)
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{`"a,b`})
+			require.True(t, errors.Is(err, list.UnterminatedQuote))
+
+			value, err = typemapper.ValidateAndMap(ctx, list.Of(scalar.String()).SetSeparator(';').SetQuote(0), "field", []string{`"a,b";c`})
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{`"a,b"`, "c"}, value)
+		},
+	)
+
+	t.Run(
+		"ItemsCount",
+		func(t *testing.T) {
+			mapper := list.Of(scalar.Int()).SetMinItems(1).SetMaxItems(2)
+
+			_, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{""})
+			require.True(t, errors.Is(err, list.TooFewItems))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1,2,3"})
+			require.True(t, errors.Is(err, list.TooManyItems))
+		},
+	)
+
+	t.Run(
+		"ItemError",
+		func(t *testing.T) {
+			_, err := typemapper.ValidateAndMap(ctx, list.Of(scalar.Int()), "field", []string{"1,x"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+
+			var itemErr *list.ItemError
+			require.True(t, errors.As(err, &itemErr))
+			require.Equal(t, 1, itemErr.Index)
+			require.Equal(t, "x", itemErr.Value)
+		},
+	)
+}
@@ -0,0 +1,37 @@
+package typemapper
+
+// MergeValidateFieldFunc return ValidateFieldFunc that run funcs in order,
+// nil funcs are skipped
+func MergeValidateFieldFunc(funcs ...ValidateFieldFunc) ValidateFieldFunc {
+	return func(field string) error {
+		for _, f := range funcs {
+			if f == nil {
+				continue
+			}
+
+			if err := f(field); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// MergeValidateValuesFunc return ValidateValuesFunc that run funcs in order,
+// nil funcs are skipped
+func MergeValidateValuesFunc(funcs ...ValidateValuesFunc) ValidateValuesFunc {
+	return func(values []string) error {
+		for _, f := range funcs {
+			if f == nil {
+				continue
+			}
+
+			if err := f(values); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
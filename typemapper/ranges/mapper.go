@@ -0,0 +1,136 @@
+package ranges
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+)
+
+// RangeMapper map value to Range
+type RangeMapper struct {
+	bound typemapper.QueryTypeMapper
+
+	compare CompareFunc
+}
+
+// Of return mapper of Range which bounds are mapped by bound,
+// for example
+//
+//	ranges.Of(scalar.Int())
+//	ranges.Of(scalar.Time().SetLayouts(scalar.DateOnly))
+//
+// Bounds are compared by Compare
+func Of(bound typemapper.QueryTypeMapper) *RangeMapper {
+	return &RangeMapper{
+		bound:   bound,
+		compare: Compare,
+	}
+}
+
+// SetCompare set CompareFunc of bounds of other types than Compare support
+func (r *RangeMapper) SetCompare(compare CompareFunc) *RangeMapper {
+	r.compare = compare
+	return r
+}
+
+// Can validateField format
+func (r *RangeMapper) ValidateField(field string) error {
+	return r.bound.ValidateField(field)
+}
+
+// Can validate values count or type if needed(string, json, or users type)
+func (r *RangeMapper) ValidateValues(values []string) error {
+	_, err := scalar.OneValue(values)
+	return err
+}
+
+func (r *RangeMapper) Map(field string, values []string) (interface{}, error) {
+	return r.MapContext(context.Background(), field, values)
+}
+
+// Implement typemapper.ContextQueryTypeMapper interface
+func (r *RangeMapper) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	value, err := scalar.OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max, result, err := parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Min, err = r.mapBound(ctx, field, min); err != nil {
+		return nil, fmt.Errorf("Min of range: %w", err)
+	}
+
+	if result.Max, err = r.mapBound(ctx, field, max); err != nil {
+		return nil, fmt.Errorf("Max of range: %w", err)
+	}
+
+	if err := result.check(r.compare); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mapBound map bound, nil for unbounded side
+func (r *RangeMapper) mapBound(ctx context.Context, field, value string) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if err := r.bound.ValidateValues([]string{value}); err != nil {
+		return nil, err
+	}
+
+	return typemapper.MapContext(ctx, r.bound, field, []string{value})
+}
+
+// parse split value to bounds and return Range with inclusive flags
+//
+// Supported forms:
+//
+//	min..max  min,max  [min,max]  (min,max)  [min,max)  (min,max]
+//
+// where min or max can be omitted
+func parse(value string) (min, max string, result Range, err error) {
+	value = strings.TrimSpace(value)
+
+	var bounds []string
+	switch {
+	case len(value) >= 2 && strings.ContainsAny(value[:1], "[(") && strings.ContainsAny(value[len(value)-1:], "])"):
+		result.MinInclusive = value[0] == '['
+		result.MaxInclusive = value[len(value)-1] == ']'
+		bounds = strings.Split(value[1:len(value)-1], ",")
+	case strings.Contains(value, ".."):
+		result.MinInclusive, result.MaxInclusive = true, true
+		bounds = strings.SplitN(value, "..", 2)
+	default:
+		result.MinInclusive, result.MaxInclusive = true, true
+		bounds = strings.Split(value, ",")
+	}
+
+	if len(bounds) != 2 {
+		return "", "", result, fmt.Errorf("%w: %q, expect min..max or min,max", BadRange, value)
+	}
+
+	min, max = strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+	if min == "" && max == "" {
+		return "", "", result, fmt.Errorf("%w: %q have no bounds", BadRange, value)
+	}
+
+	if min == "" {
+		result.MinInclusive = false
+	}
+
+	if max == "" {
+		result.MaxInclusive = false
+	}
+
+	return min, max, result, nil
+}
@@ -0,0 +1,59 @@
+package ranges
+
+import (
+	"regexp"
+
+	queryparser "github.com/0B1t322/QueryParser"
+)
+
+// Paired return regex key and item of ParseSchema
+// that map fields name_from and name_to to one Range under name
+//
+//	key, item := ranges.Paired("price", ranges.Of(scalar.Float()))
+//	schema[key] = item
+//
+// Both bounds are inclusive, omitted field mean unbounded side,
+// errors of bounds are put under their fields
+func Paired(name string, mapper *RangeMapper) (string, queryparser.ParseSchemaItem) {
+	key := regexp.QuoteMeta(name) + `_(?P<side>from|to)`
+
+	return key, queryparser.ParseSchemaItem{
+		IsRegex: true,
+		Mapper:  mapper.bound,
+		FinalizeParseFunc: func(result queryparser.ParseResult, field string, value queryparser.ResultOrError) {
+			if value.IsError() {
+				result[field] = value
+				return
+			}
+
+			current := result[name]
+			if current.IsError() {
+				return
+			}
+
+			r, _ := current.Result.(Range)
+			if value.Captures["side"] == "from" {
+				r.Min, r.MinInclusive = value.Result, value.Result != nil
+			} else {
+				r.Max, r.MaxInclusive = value.Result, value.Result != nil
+			}
+
+			if err := r.check(mapper.compare); err != nil {
+				result[name] = queryparser.ResultOrError{
+					Err: &queryparser.FieldError{
+						Field: name,
+						Key:   key,
+						Stage: queryparser.StageValidate,
+						Code:  queryparser.CodeValidationFailed,
+						Err:   err,
+					},
+					Stage:    queryparser.StageValidate,
+					Presence: queryparser.PresenceValue,
+				}
+				return
+			}
+
+			result[name] = queryparser.ResultOrError{Result: r, Presence: queryparser.PresenceValue}
+		},
+	}
+}
@@ -0,0 +1,141 @@
+// Package ranges provide typemapper.QueryTypeMapper of ranges
+// which bounds are mapped with bound mapper
+//
+// Ranges can be written inline
+//
+//	age=18..65
+//	price[between]=10,20
+//	created=[2021-01-01,2022-01-01)
+//	age=18..
+//
+// or by paired fields, see Paired
+//
+//	price_from=10&price_to=20
+package ranges
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// BadRange returned if value is not a range
+	BadRange = errors.New("Bad range")
+
+	// InvertedRange returned if min of range is greater than max
+	// or range is empty
+	InvertedRange = errors.New("Inverted range")
+
+	// UnsupportedType returned by Compare for values of unknown type
+	UnsupportedType = errors.New("Unsupported type")
+)
+
+// Range of values, nil Min or Max mean unbounded side
+type Range struct {
+	Min, Max interface{}
+
+	MinInclusive, MaxInclusive bool
+}
+
+// String return range in interval notation
+func (r Range) String() string {
+	var b strings.Builder
+	if r.MinInclusive {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+
+	if r.Min != nil {
+		fmt.Fprint(&b, r.Min)
+	}
+	b.WriteByte(',')
+	if r.Max != nil {
+		fmt.Fprint(&b, r.Max)
+	}
+
+	if r.MaxInclusive {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+
+	return b.String()
+}
+
+// check return InvertedRange error if range have no values
+func (r Range) check(compare CompareFunc) error {
+	if r.Min == nil || r.Max == nil {
+		return nil
+	}
+
+	cmp, err := compare(r.Min, r.Max)
+	if err != nil {
+		return err
+	}
+
+	if cmp > 0 || cmp == 0 && !(r.MinInclusive && r.MaxInclusive) {
+		return fmt.Errorf("%w: %v", InvertedRange, r)
+	}
+
+	return nil
+}
+
+// CompareFunc return -1, 0 or 1 if a is lower, equal or greater than b
+type CompareFunc func(a, b interface{}) (int, error)
+
+// Compare is CompareFunc of int, int64, float64, string, time.Time and time.Duration
+func Compare(a, b interface{}) (int, error) {
+	switch a := a.(type) {
+	case int:
+		if b, ok := b.(int); ok {
+			return compareInt64(int64(a), int64(b)), nil
+		}
+	case int64:
+		if b, ok := b.(int64); ok {
+			return compareInt64(a, b), nil
+		}
+	case time.Duration:
+		if b, ok := b.(time.Duration); ok {
+			return compareInt64(int64(a), int64(b)), nil
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			switch {
+			case a < b:
+				return -1, nil
+			case a > b:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b), nil
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			switch {
+			case a.Before(b):
+				return -1, nil
+			case a.After(b):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: can't compare %T and %T", UnsupportedType, a, b)
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,154 @@
+package ranges_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	queryparser "github.com/0B1t322/QueryParser"
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/ranges"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunc_Range(t *testing.T) {
+	t.Run(
+		"Forms",
+		func(t *testing.T) {
+			mapper := ranges.Of(scalar.Int())
+
+			for value, expect := range map[string]ranges.Range{
+				"18..65":  {Min: 18, Max: 65, MinInclusive: true, MaxInclusive: true},
+				"10,20":   {Min: 10, Max: 20, MinInclusive: true, MaxInclusive: true},
+				"[1,5)":   {Min: 1, Max: 5, MinInclusive: true},
+				"(1,5]":   {Min: 1, Max: 5, MaxInclusive: true},
+				"18..":    {Min: 18, MinInclusive: true},
+				"..65":    {Max: 65, MaxInclusive: true},
+				"(,5)":    {Max: 5},
+				" 1 .. 1": {Min: 1, Max: 1, MinInclusive: true, MaxInclusive: true},
+			} {
+				result, err := mapper.Map("age", []string{value})
+				require.NoError(t, err, value)
+				require.Equal(t, expect, result, value)
+			}
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			mapper := ranges.Of(scalar.Int())
+
+			for value, expect := range map[string]error{
+				"65..18":  ranges.InvertedRange,
+				"(1,1]":   ranges.InvertedRange,
+				"..":      ranges.BadRange,
+				"18":      ranges.BadRange,
+				"[1,2,3]": ranges.BadRange,
+				"1..x":    scalar.BadSyntax,
+			} {
+				_, err := mapper.Map("age", []string{value})
+				require.True(t, errors.Is(err, expect), "%s: %v", value, err)
+			}
+
+			require.True(t, errors.Is(mapper.ValidateValues([]string{"1..2", "3..4"}), scalar.ExpectOneValue))
+			_, mapErr := mapper.Map("field", nil)
+			require.True(t, errors.Is(mapErr, scalar.ExpectOneValue))
+		},
+	)
+
+	t.Run(
+		"Types",
+		func(t *testing.T) {
+			result, err := ranges.Of(scalar.Float()).Map("price", []string{"1.5..2.5"})
+			require.NoError(t, err)
+			require.Equal(t, ranges.Range{Min: 1.5, Max: 2.5, MinInclusive: true, MaxInclusive: true}, result)
+
+			result, err = ranges.Of(scalar.Time().SetLayouts(scalar.DateOnly)).Map("created", []string{"[2021-01-01,2022-01-01)"})
+			require.NoError(t, err)
+			require.Equal(t, "[2021-01-01 00:00:00 +0000 UTC,2022-01-01 00:00:00 +0000 UTC)", result.(ranges.Range).String())
+
+			_, err = ranges.Of(scalar.Duration()).Map("took", []string{"1h..1m"})
+			require.True(t, errors.Is(err, ranges.InvertedRange))
+
+			_, err = ranges.Of(scalar.UUIDValue()).Map("id", []string{
+				"123e4567-e89b-12d3-a456-426614174000..123e4567-e89b-12d3-a456-426614174001",
+			})
+			require.True(t, errors.Is(err, ranges.UnsupportedType))
+		},
+	)
+
+	t.Run(
+		"Factory",
+		func(t *testing.T) {
+			factory := typemapper.NewQueryTypeFactory().
+				AddField(`price\[between\]`, ranges.Of(scalar.Int()))
+
+			result, err := factory.MapRegexField("price[between]", []string{"10,20"})
+			require.NoError(t, err)
+			require.Equal(t, ranges.Range{Min: 10, Max: 20, MinInclusive: true, MaxInclusive: true}, result)
+		},
+	)
+}
+
+func TestFunc_RangePaired(t *testing.T) {
+	key, item := ranges.Paired("price", ranges.Of(scalar.Int()))
+	p := queryparser.New(
+		typemapper.NewQueryTypeFactory(),
+		queryparser.ParseSchema{key: item},
+	).MustCompile()
+
+	t.Run(
+		"Both",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"price_from": {"10"}, "price_to": {"20"}})
+			require.False(t, result.HasErrors())
+			require.Equal(t, ranges.Range{Min: 10, Max: 20, MinInclusive: true, MaxInclusive: true}, result["price"].Result)
+		},
+	)
+
+	t.Run(
+		"Open",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"price_to": {"20"}})
+			require.Equal(t, ranges.Range{Max: 20, MaxInclusive: true}, result["price"].Result)
+		},
+	)
+
+	t.Run(
+		"Inverted",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"price_from": {"30"}, "price_to": {"20"}})
+			require.ErrorIs(t, result["price"].Err, ranges.InvertedRange)
+			require.ErrorIs(t, result.Err(), queryparser.CodeValidationFailed)
+		},
+	)
+
+	t.Run(
+		"BadBound",
+		func(t *testing.T) {
+			result := p.ParseUrlValues(url.Values{"price_from": {"ten"}, "price_to": {"20"}})
+			require.ErrorIs(t, result["price_from"].Err, scalar.BadSyntax)
+			require.Equal(t, ranges.Range{Max: 20, MaxInclusive: true}, result["price"].Result)
+		},
+	)
+
+	t.Run(
+		"Time",
+		func(t *testing.T) {
+			key, item := ranges.Paired("created", ranges.Of(scalar.Time().SetLayouts(scalar.DateOnly)))
+			result := queryparser.New(
+				typemapper.NewQueryTypeFactory(),
+				queryparser.ParseSchema{key: item},
+			).ParseUrlValues(url.Values{"created_from": {"2021-01-01"}})
+
+			require.Equal(
+				t,
+				ranges.Range{Min: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), MinInclusive: true},
+				result["created"].Result,
+			)
+		},
+	)
+}
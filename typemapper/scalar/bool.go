@@ -0,0 +1,40 @@
+package scalar
+
+import "strings"
+
+// BoolMapper map value to bool
+type BoolMapper struct {
+	base
+}
+
+// Bool return mapper of bool value,
+// it accept values of strconv.ParseBool and yes, no, on, off
+func Bool() *BoolMapper {
+	return &BoolMapper{}
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject
+func (b *BoolMapper) SetEmpty(empty Empty) *BoolMapper {
+	b.empty = empty
+	return b
+}
+
+func (b *BoolMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return b.mapEmpty(false)
+	}
+
+	switch strings.ToLower(value) {
+	case "1", "t", "true", "yes", "on":
+		return true, nil
+	case "0", "f", "false", "no", "off":
+		return false, nil
+	}
+
+	return nil, badSyntax(value, "a boolean")
+}
@@ -0,0 +1,6 @@
+// Package scalar provide ready to use typemapper.QueryTypeMapper
+// for common scalar types: int, float, bool, string, time, duration, UUID and enum
+//
+// Each mapper expect exactly one value and can be passed to
+// queryparser.ParseSchemaItem.Mapper or typemapper.QueryTypeFactory.AddField
+package scalar
@@ -0,0 +1,62 @@
+package scalar
+
+import (
+	"strings"
+	"time"
+)
+
+// DurationMapper map value to time.Duration
+type DurationMapper struct {
+	base
+
+	min, max *time.Duration
+}
+
+// Duration return mapper of time.Duration value in format of time.ParseDuration
+func Duration() *DurationMapper {
+	return &DurationMapper{}
+}
+
+// SetMin set minimal allowed duration
+func (d *DurationMapper) SetMin(min time.Duration) *DurationMapper {
+	d.min = &min
+	return d
+}
+
+// SetMax set maximal allowed duration
+func (d *DurationMapper) SetMax(max time.Duration) *DurationMapper {
+	d.max = &max
+	return d
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject
+func (d *DurationMapper) SetEmpty(empty Empty) *DurationMapper {
+	d.empty = empty
+	return d
+}
+
+func (d *DurationMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return d.mapEmpty(time.Duration(0))
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, badSyntax(value, "a duration")
+	}
+
+	if d.min != nil && duration < *d.min {
+		return nil, outOfRange(duration, "lower than", *d.min)
+	}
+
+	if d.max != nil && duration > *d.max {
+		return nil, outOfRange(duration, "greater than", *d.max)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,142 @@
+package scalar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NotAllowedValue matches EnumError
+var NotAllowedValue = errors.New("Value not allowed")
+
+// EnumError returned by EnumMapper if value is not one of allowed
+type EnumError struct {
+	Value string
+
+	// Allowed values in order of adding
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("Value %q is not allowed, allowed values: %s", e.Value, strings.Join(e.Allowed, ", "))
+}
+
+func (e *EnumError) Is(target error) bool {
+	return target == NotAllowedValue
+}
+
+// EnumMapper map value to one of user defined constants
+type EnumMapper struct {
+	base
+
+	caseInsensitive bool
+
+	// names in order of adding
+	names []string
+
+	// constants by names and aliases
+	values map[string]interface{}
+
+	// aliases by names
+	aliases map[string][]string
+}
+
+// Enum return mapper of user defined constants,
+// constants are added by Add
+func Enum() *EnumMapper {
+	return &EnumMapper{
+		values:  map[string]interface{}{},
+		aliases: map[string][]string{},
+	}
+}
+
+// EnumStrings return enum mapper where each value is mapped to itself
+func EnumStrings(values ...string) *EnumMapper {
+	enum := Enum()
+	for _, value := range values {
+		enum.Add(value, value)
+	}
+
+	return enum
+}
+
+// Add add constant that mapped from name and aliases, for example
+//
+//	Enum().Add("asc", Asc, "ascending").Add("desc", Desc, "descending")
+//
+// If name is added again all its aliases are mapped to new constant
+func (e *EnumMapper) Add(name string, value interface{}, aliases ...string) *EnumMapper {
+	if _, find := e.aliases[name]; !find {
+		e.names = append(e.names, name)
+	}
+
+	e.aliases[name] = append(e.aliases[name], aliases...)
+	e.values[name] = value
+	for _, alias := range e.aliases[name] {
+		e.values[alias] = value
+	}
+
+	return e
+}
+
+// SetCaseInsensitive set case insensitive matching of names and aliases
+func (e *EnumMapper) SetCaseInsensitive(caseInsensitive bool) *EnumMapper {
+	e.caseInsensitive = caseInsensitive
+	return e
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject,
+// EmptyAsZero map it to nil
+func (e *EnumMapper) SetEmpty(empty Empty) *EnumMapper {
+	e.empty = empty
+	return e
+}
+
+// Allowed return names of constants in order of adding
+func (e *EnumMapper) Allowed() []string {
+	return append([]string(nil), e.names...)
+}
+
+// Aliases return aliases of name
+func (e *EnumMapper) Aliases(name string) []string {
+	return append([]string(nil), e.aliases[name]...)
+}
+
+func (e *EnumMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return e.mapEmpty(nil)
+	}
+
+	if constant, find := e.lookup(value); find {
+		return constant, nil
+	}
+
+	return nil, &EnumError{Value: value, Allowed: e.Allowed()}
+}
+
+func (e *EnumMapper) lookup(value string) (interface{}, bool) {
+	if constant, find := e.values[value]; find {
+		return constant, true
+	} else if !e.caseInsensitive {
+		return nil, false
+	}
+
+	for _, name := range e.names {
+		if strings.EqualFold(name, value) {
+			return e.values[name], true
+		}
+
+		for _, alias := range e.aliases[name] {
+			if strings.EqualFold(alias, value) {
+				return e.values[alias], true
+			}
+		}
+	}
+
+	return nil, false
+}
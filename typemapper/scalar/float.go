@@ -0,0 +1,66 @@
+package scalar
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FloatMapper map value to float64
+type FloatMapper struct {
+	base
+
+	min, max *float64
+}
+
+// Float return mapper of float64 value, NaN and Inf are rejected
+func Float() *FloatMapper {
+	return &FloatMapper{}
+}
+
+// SetMin set minimal allowed value
+func (f *FloatMapper) SetMin(min float64) *FloatMapper {
+	f.min = &min
+	return f
+}
+
+// SetMax set maximal allowed value
+func (f *FloatMapper) SetMax(max float64) *FloatMapper {
+	f.max = &max
+	return f
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject
+func (f *FloatMapper) SetEmpty(empty Empty) *FloatMapper {
+	f.empty = empty
+	return f
+}
+
+func (f *FloatMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return f.mapEmpty(float64(0))
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return nil, outOfRange(value, "out of", "float64")
+	} else if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return nil, badSyntax(value, "a number")
+	}
+
+	if f.min != nil && number < *f.min {
+		return nil, outOfRange(number, "lower than", *f.min)
+	}
+
+	if f.max != nil && number > *f.max {
+		return nil, outOfRange(number, "greater than", *f.max)
+	}
+
+	return number, nil
+}
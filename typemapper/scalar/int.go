@@ -0,0 +1,65 @@
+package scalar
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// IntMapper map value to int
+type IntMapper struct {
+	base
+
+	min, max *int
+}
+
+// Int return mapper of int value
+func Int() *IntMapper {
+	return &IntMapper{}
+}
+
+// SetMin set minimal allowed value
+func (i *IntMapper) SetMin(min int) *IntMapper {
+	i.min = &min
+	return i
+}
+
+// SetMax set maximal allowed value
+func (i *IntMapper) SetMax(max int) *IntMapper {
+	i.max = &max
+	return i
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject
+func (i *IntMapper) SetEmpty(empty Empty) *IntMapper {
+	i.empty = empty
+	return i
+}
+
+func (i *IntMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return i.mapEmpty(0)
+	}
+
+	number, err := strconv.Atoi(value)
+	if errors.Is(err, strconv.ErrRange) {
+		return nil, outOfRange(value, "out of", "int")
+	} else if err != nil {
+		return nil, badSyntax(value, "an integer")
+	}
+
+	if i.min != nil && number < *i.min {
+		return nil, outOfRange(number, "lower than", *i.min)
+	}
+
+	if i.max != nil && number > *i.max {
+		return nil, outOfRange(number, "greater than", *i.max)
+	}
+
+	return number, nil
+}
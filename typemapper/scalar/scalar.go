@@ -0,0 +1,79 @@
+package scalar
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ExpectOneValue returned by ValidateValues if values count is not one
+	ExpectOneValue = errors.New("Expect one value")
+
+	// EmptyValue returned by Map if value is empty and EmptyReject is set
+	EmptyValue = errors.New("Empty value")
+
+	// BadSyntax matches errors of values that can't be parsed
+	BadSyntax = errors.New("Bad syntax")
+
+	// OutOfRange matches errors of values out of bounds
+	OutOfRange = errors.New("Value out of range")
+)
+
+// Empty describe how mapper treat empty string
+type Empty int
+
+const (
+	// EmptyReject return EmptyValue error
+	EmptyReject Empty = iota
+
+	// EmptyAsNil map empty string to nil
+	EmptyAsNil
+
+	// EmptyAsZero map empty string to zero value of type
+	EmptyAsZero
+)
+
+// base implement common parts of scalar mappers
+type base struct {
+	empty Empty
+}
+
+// OneValue return single value of values or ExpectOneValue error,
+// Map of single value mappers use it because it can be called without ValidateValues
+func OneValue(values []string) (string, error) {
+	if len(values) != 1 {
+		return "", fmt.Errorf("%w, got %d", ExpectOneValue, len(values))
+	}
+	return values[0], nil
+}
+
+// Can validate values count
+func (b *base) ValidateValues(values []string) error {
+	_, err := OneValue(values)
+	return err
+}
+
+// Can validateField format
+func (b *base) ValidateField(field string) error {
+	return nil
+}
+
+// mapEmpty return result for empty value according to Empty policy
+func (b *base) mapEmpty(zero interface{}) (interface{}, error) {
+	switch b.empty {
+	case EmptyAsNil:
+		return nil, nil
+	case EmptyAsZero:
+		return zero, nil
+	}
+
+	return nil, EmptyValue
+}
+
+func badSyntax(value, kind string) error {
+	return fmt.Errorf("%w: %q is not %s", BadSyntax, value, kind)
+}
+
+func outOfRange(value interface{}, bound string, limit interface{}) error {
+	return fmt.Errorf("%w: %v is %s %v", OutOfRange, value, bound, limit)
+}
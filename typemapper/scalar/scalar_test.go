@@ -0,0 +1,253 @@
+package scalar_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunc_Scalar(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run(
+		"ExpectOneValue",
+		func(t *testing.T) {
+			_, err := typemapper.ValidateAndMap(ctx, scalar.Int(), "field", []string{"1", "2"})
+			require.True(t, errors.Is(err, scalar.ExpectOneValue))
+
+			_, err = typemapper.ValidateAndMap(ctx, scalar.Int(), "field", nil)
+			require.True(t, errors.Is(err, scalar.ExpectOneValue))
+
+			// Map don't expect that values are validated
+			for _, mapper := range []typemapper.QueryTypeMapper{
+				scalar.Int(), scalar.Float(), scalar.Bool(), scalar.String(), scalar.Time(),
+				scalar.Duration(), scalar.UUIDValue(), scalar.EnumStrings("a"),
+			} {
+				_, err = mapper.Map("field", nil)
+				require.True(t, errors.Is(err, scalar.ExpectOneValue), "%T", mapper)
+			}
+
+			_, err = typemapper.NewQueryTypeFactory().AddField("x", scalar.Int()).MapField("x", nil)
+			require.True(t, errors.Is(err, scalar.ExpectOneValue))
+
+			_, err = typemapper.Chain(scalar.Int()).Map("field", []string{"1", "2"})
+			require.True(t, errors.Is(err, scalar.ExpectOneValue))
+		},
+	)
+
+	t.Run(
+		"Int",
+		func(t *testing.T) {
+			mapper := scalar.Int().SetMin(0).SetMax(100)
+
+			value, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{" 42 "})
+			require.NoError(t, err)
+			require.Equal(t, 42, value)
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"4x"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"101"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"-1"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"99999999999999999999"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+		},
+	)
+
+	t.Run(
+		"Empty",
+		func(t *testing.T) {
+			_, err := typemapper.ValidateAndMap(ctx, scalar.Int(), "field", []string{""})
+			require.True(t, errors.Is(err, scalar.EmptyValue))
+
+			value, err := typemapper.ValidateAndMap(ctx, scalar.Int().SetEmpty(scalar.EmptyAsNil), "field", []string{""})
+			require.NoError(t, err)
+			require.Nil(t, value)
+
+			value, err = typemapper.ValidateAndMap(ctx, scalar.Int().SetEmpty(scalar.EmptyAsZero), "field", []string{""})
+			require.NoError(t, err)
+			require.Equal(t, 0, value)
+
+			value, err = typemapper.ValidateAndMap(ctx, scalar.String().SetEmpty(scalar.EmptyAsZero), "field", []string{""})
+			require.NoError(t, err)
+			require.Equal(t, "", value)
+		},
+	)
+
+	t.Run(
+		"Float",
+		func(t *testing.T) {
+			mapper := scalar.Float().SetMax(1)
+
+			value, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{"0.5"})
+			require.NoError(t, err)
+			require.Equal(t, 0.5, value)
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"NaN"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1.5"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+		},
+	)
+
+	t.Run(
+		"Bool",
+		func(t *testing.T) {
+			for value, expect := range map[string]bool{"true": true, "1": true, "Yes": true, "off": false, "F": false} {
+				result, err := typemapper.ValidateAndMap(ctx, scalar.Bool(), "field", []string{value})
+				require.NoError(t, err, value)
+				require.Equal(t, expect, result, value)
+			}
+
+			_, err := typemapper.ValidateAndMap(ctx, scalar.Bool(), "field", []string{"maybe"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+		},
+	)
+
+	t.Run(
+		"String",
+		func(t *testing.T) {
+			mapper := scalar.String().SetTrim(true).SetMinLength(2).SetMaxLength(4)
+
+			value, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{" абв "})
+			require.NoError(t, err)
+			require.Equal(t, "абв", value)
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"a"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"abcde"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+		},
+	)
+
+	t.Run(
+		"Time",
+		func(t *testing.T) {
+			moscow := time.FixedZone("MSK", 3*60*60)
+			mapper := scalar.Time().
+				SetLayouts(scalar.RFC3339, scalar.DateOnly, scalar.UnixSeconds).
+				SetLocation(moscow).
+				SetMin(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			value, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{"2021-05-01T10:00:00Z"})
+			require.NoError(t, err)
+			require.True(t, time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC).Equal(value.(time.Time)))
+
+			value, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"2021-05-01"})
+			require.NoError(t, err)
+			require.Equal(t, time.Date(2021, 5, 1, 0, 0, 0, 0, moscow), value)
+
+			value, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1620000000"})
+			require.NoError(t, err)
+			require.Equal(t, int64(1620000000), value.(time.Time).Unix())
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1999-12-31"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"yesterday"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+		},
+	)
+
+	t.Run(
+		"Duration",
+		func(t *testing.T) {
+			mapper := scalar.Duration().SetMax(time.Hour)
+
+			value, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{"1m30s"})
+			require.NoError(t, err)
+			require.Equal(t, 90*time.Second, value)
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"2h"})
+			require.True(t, errors.Is(err, scalar.OutOfRange))
+
+			_, err = typemapper.ValidateAndMap(ctx, mapper, "field", []string{"10"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+		},
+	)
+
+	t.Run(
+		"UUID",
+		func(t *testing.T) {
+			value, err := typemapper.ValidateAndMap(ctx, scalar.UUIDValue(), "field", []string{"123E4567-e89b-12d3-a456-426614174000"})
+			require.NoError(t, err)
+			require.Equal(t, "123e4567-e89b-12d3-a456-426614174000", value.(scalar.UUID).String())
+
+			_, err = typemapper.ValidateAndMap(ctx, scalar.UUIDValue(), "field", []string{"123e4567e89b12d3a456426614174000"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+
+			_, err = typemapper.ValidateAndMap(ctx, scalar.UUIDValue(), "field", []string{"123e4567-e89b-12d3-a456-42661417400z"})
+			require.True(t, errors.Is(err, scalar.BadSyntax))
+		},
+	)
+
+	t.Run(
+		"Enum",
+		func(t *testing.T) {
+			type Order int
+			const (
+				Asc Order = iota
+				Desc
+			)
+
+			mapper := scalar.Enum().
+				Add("asc", Asc, "ascending").
+				Add("desc", Desc, "descending").
+				SetCaseInsensitive(true)
+
+			require.Equal(t, []string{"asc", "desc"}, mapper.Allowed())
+			require.Equal(t, []string{"descending"}, mapper.Aliases("desc"))
+
+			for value, expect := range map[string]Order{"asc": Asc, "ASC": Asc, "ascending": Asc, "Descending": Desc} {
+				result, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{value})
+				require.NoError(t, err, value)
+				require.Equal(t, expect, result, value)
+			}
+
+			_, err := typemapper.ValidateAndMap(ctx, mapper, "field", []string{"up"})
+			require.True(t, errors.Is(err, scalar.NotAllowedValue))
+			require.EqualError(t, err, `Value "up" is not allowed, allowed values: asc, desc`)
+
+			var enumErr *scalar.EnumError
+			require.True(t, errors.As(err, &enumErr))
+			require.Equal(t, []string{"asc", "desc"}, enumErr.Allowed)
+
+			// aliases of added again name are mapped to new constant
+			readded := scalar.Enum().Add("a", 1, "x").Add("a", 2, "y")
+			require.Equal(t, []string{"a"}, readded.Allowed())
+			for _, value := range []string{"a", "x", "y"} {
+				result, err := typemapper.ValidateAndMap(ctx, readded, "field", []string{value})
+				require.NoError(t, err)
+				require.Equal(t, 2, result, value)
+			}
+
+			_, err = typemapper.ValidateAndMap(ctx, scalar.EnumStrings("eq", "lte"), "field", []string{"EQ"})
+			require.True(t, errors.Is(err, scalar.NotAllowedValue))
+		},
+	)
+
+	t.Run(
+		"Factory",
+		func(t *testing.T) {
+			factory := typemapper.NewQueryTypeFactory().
+				AddField("limit", scalar.Int().SetMin(1))
+
+			require.True(t, errors.Is(factory.Validate("limit", []string{"1", "2"}), typemapper.InvalidValues))
+
+			value, err := factory.MapField("limit", []string{"10"})
+			require.NoError(t, err)
+			require.Equal(t, 10, value)
+		},
+	)
+}
@@ -0,0 +1,69 @@
+package scalar
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// StringMapper map value to string
+type StringMapper struct {
+	base
+
+	trim bool
+
+	minLength, maxLength *int
+}
+
+// String return mapper of string value
+func String() *StringMapper {
+	return &StringMapper{}
+}
+
+// SetTrim set trimming of spaces around value
+func (s *StringMapper) SetTrim(trim bool) *StringMapper {
+	s.trim = trim
+	return s
+}
+
+// SetMinLength set minimal length of value in runes
+func (s *StringMapper) SetMinLength(min int) *StringMapper {
+	s.minLength = &min
+	return s
+}
+
+// SetMaxLength set maximal length of value in runes
+func (s *StringMapper) SetMaxLength(max int) *StringMapper {
+	s.maxLength = &max
+	return s
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject
+func (s *StringMapper) SetEmpty(empty Empty) *StringMapper {
+	s.empty = empty
+	return s
+}
+
+func (s *StringMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	if s.trim {
+		value = strings.TrimSpace(value)
+	}
+
+	if value == "" {
+		return s.mapEmpty("")
+	}
+
+	length := utf8.RuneCountInString(value)
+	if s.minLength != nil && length < *s.minLength {
+		return nil, outOfRange(length, "length lower than", *s.minLength)
+	}
+
+	if s.maxLength != nil && length > *s.maxLength {
+		return nil, outOfRange(length, "length greater than", *s.maxLength)
+	}
+
+	return value, nil
+}
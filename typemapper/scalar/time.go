@@ -0,0 +1,113 @@
+package scalar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RFC3339 layout of time with zone
+	RFC3339 = time.RFC3339
+
+	// DateOnly layout of date without time
+	DateOnly = "2006-01-02"
+
+	// UnixSeconds is special layout of seconds since unix epoch
+	UnixSeconds = "unix"
+)
+
+// TimeMapper map value to time.Time
+type TimeMapper struct {
+	base
+
+	layouts []string
+
+	location *time.Location
+
+	min, max *time.Time
+}
+
+// Time return mapper of time.Time value,
+// default layout is RFC3339
+func Time() *TimeMapper {
+	return &TimeMapper{
+		layouts:  []string{RFC3339},
+		location: time.UTC,
+	}
+}
+
+// SetLayouts set layouts that are tried in order,
+// layouts can be time package layouts, DateOnly or UnixSeconds
+func (t *TimeMapper) SetLayouts(layouts ...string) *TimeMapper {
+	t.layouts = layouts
+	return t
+}
+
+// SetLocation set location of values without zone, default is UTC
+func (t *TimeMapper) SetLocation(location *time.Location) *TimeMapper {
+	t.location = location
+	return t
+}
+
+// SetMin set minimal allowed time
+func (t *TimeMapper) SetMin(min time.Time) *TimeMapper {
+	t.min = &min
+	return t
+}
+
+// SetMax set maximal allowed time
+func (t *TimeMapper) SetMax(max time.Time) *TimeMapper {
+	t.max = &max
+	return t
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject
+func (t *TimeMapper) SetEmpty(empty Empty) *TimeMapper {
+	t.empty = empty
+	return t
+}
+
+func (t *TimeMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return t.mapEmpty(time.Time{})
+	}
+
+	parsed, ok := t.parse(value)
+	if !ok {
+		return nil, badSyntax(value, "a time in format "+strings.Join(t.layouts, " or "))
+	}
+
+	if t.min != nil && parsed.Before(*t.min) {
+		return nil, outOfRange(parsed.Format(time.RFC3339), "before", t.min.Format(time.RFC3339))
+	}
+
+	if t.max != nil && parsed.After(*t.max) {
+		return nil, outOfRange(parsed.Format(time.RFC3339), "after", t.max.Format(time.RFC3339))
+	}
+
+	return parsed, nil
+}
+
+func (t *TimeMapper) parse(value string) (time.Time, bool) {
+	for _, layout := range t.layouts {
+		if layout == UnixSeconds {
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err == nil {
+				return time.Unix(seconds, 0).In(t.location), true
+			}
+			continue
+		}
+
+		if parsed, err := time.ParseInLocation(layout, value, t.location); err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
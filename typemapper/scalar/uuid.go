@@ -0,0 +1,80 @@
+package scalar
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// UUID is parsed UUID value
+type UUID [16]byte
+
+// String return UUID in canonical form
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UUIDMapper map value to UUID
+type UUIDMapper struct {
+	base
+}
+
+// UUIDValue return mapper of UUID value in canonical form
+//
+//	123e4567-e89b-12d3-a456-426614174000
+func UUIDValue() *UUIDMapper {
+	return &UUIDMapper{}
+}
+
+// SetEmpty set how empty string is mapped, default is EmptyReject
+func (u *UUIDMapper) SetEmpty(empty Empty) *UUIDMapper {
+	u.empty = empty
+	return u
+}
+
+func (u *UUIDMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return u.mapEmpty(UUID{})
+	}
+
+	uuid, ok := parseUUID(value)
+	if !ok {
+		return nil, badSyntax(value, "an UUID")
+	}
+
+	return uuid, nil
+}
+
+func parseUUID(value string) (UUID, bool) {
+	var uuid UUID
+	if len(value) != 36 || value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+		return uuid, false
+	}
+
+	raw := value[0:8] + value[9:13] + value[14:18] + value[19:23] + value[24:]
+	if _, err := hex.Decode(uuid[:], []byte(raw)); err != nil {
+		return uuid, false
+	}
+
+	return uuid, true
+}
@@ -1,9 +1,13 @@
 package typemapper
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
+	"sync"
 )
 
 var (
@@ -28,8 +32,11 @@ type CustomQueryTypeBuilder interface {
 	SetValidateValuesFunc(f ValidateValuesFunc) CustomQueryTypeBuilder
 	// Optional method to build QueryType
 	SetFieldFunc(f ValidateFieldFunc) CustomQueryTypeBuilder
-	// Required method to build QueryType
+	// Required method to build QueryType if SetContextTypeMapperFunc not called
 	SetTypeMapperFunc(f TypeMapperFunc) CustomQueryTypeBuilder
+	// Required method to build QueryType if SetTypeMapperFunc not called,
+	// used by MapContext instead of TypeMapperFunc
+	SetContextTypeMapperFunc(f ContextTypeMapperFunc) CustomQueryTypeBuilder
 	// Must build panic if some field are not set
 	MustBuild() QueryTypeMapper
 
@@ -48,6 +55,8 @@ type customQueryTypeValidators struct {
 
 type customQueryTypeMapper struct {
 	typeMapperFunc TypeMapperFunc
+
+	contextTypeMapperFunc ContextTypeMapperFunc
 }
 
 type customQueryType struct {
@@ -57,9 +66,20 @@ type customQueryType struct {
 }
 
 func (c *customQueryType) Map(field string, values []string) (interface{}, error) {
+	if c.typeMapperFunc == nil {
+		return c.contextTypeMapperFunc(context.Background(), field, values)
+	}
 	return c.typeMapperFunc(field, values)
 }
 
+// Implement ContextQueryTypeMapper interface
+func (c *customQueryType) MapContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	if c.contextTypeMapperFunc == nil {
+		return c.typeMapperFunc(field, values)
+	}
+	return c.contextTypeMapperFunc(ctx, field, values)
+}
+
 // Can validate values count or type if needed(string, json, or users type)
 func (c *customQueryType) ValidateValues(values []string) error {
 	if c.validateValuesFunc == nil {
@@ -90,12 +110,17 @@ func (c *customQueryType) SetTypeMapperFunc(f TypeMapperFunc) CustomQueryTypeBui
 	return c
 }
 
+func (c *customQueryType) SetContextTypeMapperFunc(f ContextTypeMapperFunc) CustomQueryTypeBuilder {
+	c.contextTypeMapperFunc = f
+	return c
+}
+
 func (c *customQueryType) Build() (QueryTypeMapper, error) {
 	// if c.field == nil {
 	// 	return nil, fmt.Errorf("Field not set")
 	// }
 
-	if c.typeMapperFunc == nil {
+	if c.typeMapperFunc == nil && c.contextTypeMapperFunc == nil {
 		return nil, fmt.Errorf("MapFunc not set")
 	}
 
@@ -115,8 +140,35 @@ func NewCustomQueryTypeBuilder() CustomQueryTypeBuilder {
 	return &customQueryType{}
 }
 
+// QueryTypeFactory match regex fields in order of adding by AddField,
+// fields added directly to Querys match after them in lexical order
+//
+// Methods of QueryTypeFactory are safe for concurrent use,
+// direct access to Querys is not
 type QueryTypeFactory struct {
 	Querys map[string]QueryTypeMapper
+
+	// RejectAmbiguous make regex methods return AmbiguousFieldError
+	// if field match more than one key
+	RejectAmbiguous bool
+
+	// WholeMatch make regex keys match whole field name
+	// like keys of queryparser.ParseSchema,
+	// by default they match part of field name
+	WholeMatch bool
+
+	// keys in order of adding
+	keys []string
+
+	// compiled keys in order of matching, nil if Compile not called
+	compiled []compiledKey
+
+	mu sync.RWMutex
+}
+
+type compiledKey struct {
+	key   string
+	regex *regexp.Regexp
 }
 
 func NewQueryTypeFactory() *QueryTypeFactory {
@@ -126,51 +178,237 @@ func NewQueryTypeFactory() *QueryTypeFactory {
 }
 
 func (q *QueryTypeFactory) AddField(field string, queryType QueryTypeMapper) *QueryTypeFactory {
-	q.Querys[field] = queryType
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addField(field, queryType)
 	return q
 }
 
+func (q *QueryTypeFactory) addField(field string, queryType QueryTypeMapper) {
+	if _, find := q.Querys[field]; !find {
+		q.keys = append(q.keys, field)
+	}
+	q.Querys[field] = queryType
+	q.compiled = nil
+}
+
+// Clone return copy of factory,
+// changes of copy don't affect original factory
+func (q *QueryTypeFactory) Clone() *QueryTypeFactory {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	clone := &QueryTypeFactory{
+		Querys:          make(map[string]QueryTypeMapper, len(q.Querys)),
+		RejectAmbiguous: q.RejectAmbiguous,
+		WholeMatch:      q.WholeMatch,
+		keys:            append([]string(nil), q.keys...),
+		compiled:        q.compiled,
+	}
+
+	for key, value := range q.Querys {
+		clone.Querys[key] = value
+	}
+
+	return clone
+}
+
+// lookup return QueryTypeMapper added with key
+func (q *QueryTypeFactory) lookup(key string) (QueryTypeMapper, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	typeMapper, find := q.Querys[key]
+	return typeMapper, find
+}
+
+// Compile compile all keys as regex and keep compiled matchers
+// for MapRegexField and ValidateRegexField
+//
+// Compile should be called again if Querys changed directly
+func (q *QueryTypeFactory) Compile() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var (
+		compiled = make([]compiledKey, 0, len(q.Querys))
+		bad      []string
+	)
+
+	for _, key := range q.orderedKeys() {
+		regex, err := regexp.Compile(q.pattern(key))
+		if err != nil {
+			bad = append(bad, fmt.Sprintf("%q: %v", key, err))
+			continue
+		}
+
+		compiled = append(compiled, compiledKey{key: key, regex: regex})
+	}
+
+	if len(bad) > 0 {
+		return fmt.Errorf("Bad regex keys: %s", strings.Join(bad, "; "))
+	}
+
+	q.compiled = compiled
+	return nil
+}
+
+// pattern return regex pattern of key that match whole field name
+// if WholeMatch is true
+func (q *QueryTypeFactory) pattern(key string) string {
+	if !q.WholeMatch {
+		return key
+	}
+
+	return `^(?:` + key + `)$`
+}
+
+// orderedKeys return keys in order of matching
+func (q *QueryTypeFactory) orderedKeys() []string {
+	var (
+		keys  = make([]string, 0, len(q.Querys))
+		added = make(map[string]bool, len(q.keys))
+	)
+
+	for _, key := range q.keys {
+		if _, find := q.Querys[key]; find && !added[key] {
+			keys = append(keys, key)
+			added[key] = true
+		}
+	}
+
+	if len(keys) == len(q.Querys) {
+		return keys
+	}
+
+	var rest []string
+	for key := range q.Querys {
+		if !added[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(keys, rest...)
+}
+
+// matchedKeys return keys which match field in order of matching,
+// if all is false return only first key
+func (q *QueryTypeFactory) matchedKeys(field string, all bool) ([]string, error) {
+	var matched []string
+	if q.compiled != nil {
+		for _, c := range q.compiled {
+			if c.regex.MatchString(field) {
+				matched = append(matched, c.key)
+				if !all {
+					break
+				}
+			}
+		}
+
+		return matched, nil
+	}
+
+	for _, key := range q.orderedKeys() {
+		match, err := regexp.MatchString(q.pattern(key), field)
+		if err != nil {
+			return nil, err
+		} else if match {
+			matched = append(matched, key)
+			if !all {
+				break
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// findRegexField return first QueryTypeMapper which key match field
+func (q *QueryTypeFactory) findRegexField(field string) (QueryTypeMapper, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	matched, err := q.matchedKeys(field, q.RejectAmbiguous)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, NotFoundField
+	case 1:
+		return q.Querys[matched[0]], nil
+	}
+
+	return nil, &AmbiguousFieldError{Field: field, Keys: matched}
+}
+
 // Implement Factory interface
 func (q *QueryTypeFactory) AddQueryTypeMapperField(field string, queryType QueryTypeMapper) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	if _, find := q.Querys[field]; !find {
-		q.AddField(field, queryType)
+		q.addField(field, queryType)
 	}
 }
 
+// Validate run ValidateField and ValidateValues of field
+//
+// cathable errors:
+// 	NotFoundField
+// 	InvalidField
+// 	InvalidValues
 func (q *QueryTypeFactory) Validate(field string, values []string) error {
-	typeMapper, find := q.Querys[field]
+	return q.ValidateKey(field, field, values)
+}
+
+// ValidateKey same as Validate but use QueryTypeMapper added with key
+func (q *QueryTypeFactory) ValidateKey(key, field string, values []string) error {
+	typeMapper, find := q.lookup(key)
 	if !find {
 		return NotFoundField
 	}
 
+	return validate(typeMapper, field, values)
+}
+
+func validate(typeMapper QueryTypeMapper, field string, values []string) error {
 	if err := typeMapper.ValidateField(field); err != nil {
-		return err
+		return fieldError(err)
 	}
 
 	if err := typeMapper.ValidateValues(values); err != nil {
-		return err
+		return valuesError(err)
 	}
 
 	return nil
 }
 
-func (q *QueryTypeFactory) ValidateRegexField(field string, values []string) error {
-	for key, value := range q.Querys {
-		if match, err := regexp.MatchString(key, field); match {
-			if err := value.ValidateField(field); err != nil {
-				return err
-			}
-		
-			if err := value.ValidateValues(values); err != nil {
-				return err
-			}
+// ValidateAndMap run ValidateField and ValidateValues of typeMapper
+// and map values if they are valid
+//
+// cathable errors:
+// 	InvalidField
+// 	InvalidValues
+func ValidateAndMap(ctx context.Context, typeMapper QueryTypeMapper, field string, values []string) (interface{}, error) {
+	if err := validate(typeMapper, field, values); err != nil {
+		return nil, err
+	}
 
-			return nil
-		} else if err != nil {
-			return err
-		}
+	return MapContext(ctx, typeMapper, field, values)
+}
+
+// ValidateRegexField same as Validate but find field by regex
+func (q *QueryTypeFactory) ValidateRegexField(field string, values []string) error {
+	value, err := q.findRegexField(field)
+	if err != nil {
+		return err
 	}
-	return NotFoundField
+
+	return validate(value, field, values)
 }
 
 // MapField map a field
@@ -178,21 +416,44 @@ func (q *QueryTypeFactory) ValidateRegexField(field string, values []string) err
 // cathable errors:
 // 	NotFoundField
 func (q *QueryTypeFactory) MapField(field string, values []string) (interface{}, error) {
-	typeMapper, find := q.Querys[field]
+	return q.MapFieldContext(context.Background(), field, values)
+}
+
+// MapFieldContext same as MapField but pass ctx to ContextQueryTypeMapper
+func (q *QueryTypeFactory) MapFieldContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	return q.MapKeyContext(ctx, field, field, values)
+}
+
+// MapKey same as MapField but use QueryTypeMapper added with key
+func (q *QueryTypeFactory) MapKey(key, field string, values []string) (interface{}, error) {
+	return q.MapKeyContext(context.Background(), key, field, values)
+}
+
+// MapKeyContext same as MapKey but pass ctx to ContextQueryTypeMapper
+func (q *QueryTypeFactory) MapKeyContext(ctx context.Context, key, field string, values []string) (interface{}, error) {
+	typeMapper, find := q.lookup(key)
 	if !find {
 		return nil, NotFoundField
 	}
 
-	return typeMapper.Map(field, values)
+	return MapContext(ctx, typeMapper, field, values)
 }
 
+// MapRegexField map a field with first QueryTypeMapper which key match field
+//
+// cathable errors:
+// 	NotFoundField
+// 	AmbiguousField
 func (q *QueryTypeFactory) MapRegexField(field string, values []string) (interface{}, error) {
-	for key, value := range q.Querys {
-		if match, err := regexp.MatchString(key, field); match {
-			return value.Map(field, values)
-		} else if err != nil {
-			return nil, err
-		}
+	return q.MapRegexFieldContext(context.Background(), field, values)
+}
+
+// MapRegexFieldContext same as MapRegexField but pass ctx to ContextQueryTypeMapper
+func (q *QueryTypeFactory) MapRegexFieldContext(ctx context.Context, field string, values []string) (interface{}, error) {
+	value, err := q.findRegexField(field)
+	if err != nil {
+		return nil, err
 	}
-	return nil, NotFoundField
+
+	return MapContext(ctx, value, field, values)
 }
@@ -1,13 +1,17 @@
 package typemapper_test
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/0B1t322/QueryParser/typemapper"
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -182,6 +186,9 @@ func TestFunc_TypeMapper(t *testing.T) {
 			require.Error(t, factory.ValidateRegexField("field_3[eq]", []string{"value_1", "value_2"}))
 			require.Error(t, factory.ValidateRegexField("field_3[lte]", []string{"value_1", "value_2"}))
 
+			require.ErrorIs(t, factory.ValidateRegexField("field_3[gte]", []string{"value_1"}), typemapper.InvalidField)
+			require.ErrorIs(t, factory.ValidateRegexField("field_3[eq]", []string{}), typemapper.InvalidValues)
+
 			require.Equal(
 				t,
 				FieldOperationString{
@@ -215,6 +222,185 @@ func TestFunc_TypeMapper(t *testing.T) {
 	)
 }
 
+func TestFunc_TypeMapperRegexOrder(t *testing.T) {
+	mapKey := func(key string) typemapper.QueryTypeMapper {
+		return typemapper.NewCustomQueryTypeBuilder().
+			SetTypeMapperFunc(
+				func(field string, values []string) (interface{}, error) {
+					return key, nil
+				},
+			).
+			MustBuild()
+	}
+
+	factory := typemapper.NewQueryTypeFactory().
+		AddField(`name\[eq\]`, mapKey("eq")).
+		AddField(`name\[.*\]`, mapKey("any"))
+
+	t.Run(
+		"Order",
+		func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				value, err := factory.MapRegexField("name[eq]", []string{"dan"})
+				require.NoError(t, err)
+				require.Equal(t, "eq", value)
+			}
+		},
+	)
+
+	t.Run(
+		"Compile",
+		func(t *testing.T) {
+			require.NoError(t, factory.Compile())
+
+			value, err := factory.MapRegexField("name[eq]", []string{"dan"})
+			require.NoError(t, err)
+			require.Equal(t, "eq", value)
+
+			require.Error(t, typemapper.NewQueryTypeFactory().AddField(`name[`, mapKey("bad")).Compile())
+		},
+	)
+
+	t.Run(
+		"RejectAmbiguous",
+		func(t *testing.T) {
+			factory.RejectAmbiguous = true
+			defer func() { factory.RejectAmbiguous = false }()
+
+			_, err := factory.MapRegexField("name[eq]", []string{"dan"})
+			require.ErrorIs(t, err, typemapper.AmbiguousField)
+			require.ErrorIs(t, factory.ValidateRegexField("name[eq]", []string{"dan"}), typemapper.AmbiguousField)
+
+			value, err := factory.MapRegexField("name[lte]", []string{"dan"})
+			require.NoError(t, err)
+			require.Equal(t, "any", value)
+		},
+	)
+
+	t.Run(
+		"WholeMatch",
+		func(t *testing.T) {
+			whole := typemapper.NewQueryTypeFactory().AddField(`name\[.*\]`, mapKey("any"))
+
+			_, err := whole.MapRegexField("xname[eq]", []string{"dan"})
+			require.NoError(t, err)
+
+			whole.WholeMatch = true
+			for _, field := range []string{"xname[eq]", "name[eq]junk"} {
+				_, err = whole.MapRegexField(field, []string{"dan"})
+				require.ErrorIs(t, err, typemapper.NotFoundField)
+			}
+
+			require.NoError(t, whole.Compile())
+			for _, field := range []string{"xname[eq]", "name[eq]junk"} {
+				require.ErrorIs(t, whole.ValidateRegexField(field, []string{"dan"}), typemapper.NotFoundField)
+			}
+
+			value, err := whole.MapRegexField("name[eq]", []string{"dan"})
+			require.NoError(t, err)
+			require.Equal(t, "any", value)
+		},
+	)
+}
+
+func TestFunc_NamedCaptures(t *testing.T) {
+	regex := regexp.MustCompile(`^(?P<field>\w+)\[(?P<op>\w+)\](?P<not>!)?$`)
+
+	require.Equal(
+		t,
+		map[string]string{"field": "name", "op": "eq"},
+		typemapper.NamedCaptures(regex, "name[eq]"),
+	)
+	require.Nil(t, typemapper.NamedCaptures(regex, "name"))
+	require.Nil(t, typemapper.NamedCaptures(nil, "name[eq]"))
+
+	mapper := typemapper.CapturesTypeMapperFunc(
+		func(field string, captures map[string]string, values []string) (interface{}, error) {
+			return captures["op"], nil
+		},
+	).WithCaptures(regex)
+
+	op, err := mapper("age[lte]", nil)
+	require.NoError(t, err)
+	require.Equal(t, "lte", op)
+}
+
+func TestFunc_TypeMapperConcurrent(t *testing.T) {
+	factory := typemapper.NewQueryTypeFactory()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			field := fmt.Sprintf("field_%d", i)
+			factory.AddQueryTypeMapperField(
+				field,
+				typemapper.NewCustomQueryTypeBuilder().
+					SetTypeMapperFunc(
+						func(field string, values []string) (interface{}, error) {
+							return values[0], nil
+						},
+					).
+					MustBuild(),
+			)
+
+			value, err := factory.MapRegexField(field, []string{field})
+			assert.NoError(t, err)
+			assert.Equal(t, field, value)
+
+			assert.NoError(t, factory.Clone().Validate(field, []string{field}))
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, factory.Querys, 50)
+}
+
+func TestFunc_TypeMapperContext(t *testing.T) {
+	type key struct{}
+
+	factory := typemapper.NewQueryTypeFactory().
+		AddField(
+			"user",
+			typemapper.NewCustomQueryTypeBuilder().
+				SetContextTypeMapperFunc(
+					func(ctx context.Context, field string, values []string) (interface{}, error) {
+						return ctx.Value(key{}), nil
+					},
+				).
+				MustBuild(),
+		).
+		AddField(
+			"name",
+			typemapper.NewCustomQueryTypeBuilder().
+				SetTypeMapperFunc(
+					func(field string, values []string) (interface{}, error) {
+						return values[0], nil
+					},
+				).
+				MustBuild(),
+		)
+
+	ctx := context.WithValue(context.Background(), key{}, "admin")
+
+	value, err := factory.MapFieldContext(ctx, "user", nil)
+	require.NoError(t, err)
+	require.Equal(t, "admin", value)
+
+	value, err = factory.MapField("user", nil)
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	value, err = factory.MapRegexFieldContext(ctx, "name", []string{"dan"})
+	require.NoError(t, err)
+	require.Equal(t, "dan", value)
+
+	_, err = typemapper.NewCustomQueryTypeBuilder().Build()
+	require.Error(t, err)
+}
+
 type FieldOperation struct {
 	Op    string      `json:"op"`
 	Value interface{} `json:"value"`
@@ -523,3 +709,166 @@ func TestFunc_TypeMapperAndOrFormat(t *testing.T) {
 		query,
 	)
 }
+
+func TestFunc_TypeMapperCombinators(t *testing.T) {
+	slug := typemapper.NewCustomQueryTypeBuilder().
+		SetValidateValuesFunc(
+			func(values []string) error {
+				if len(values) != 1 || values[0] == "" {
+					return fmt.Errorf("expect one not empty value")
+				}
+				return nil
+			},
+		).
+		SetTypeMapperFunc(
+			func(field string, values []string) (interface{}, error) {
+				if strings.ContainsAny(values[0], " /") {
+					return nil, fmt.Errorf("bad slug")
+				}
+				return values[0], nil
+			},
+		).
+		MustBuild()
+
+	t.Run(
+		"Transform",
+		func(t *testing.T) {
+			mapper := typemapper.Transform(scalar.EnumStrings("asc", "desc"), typemapper.TrimSpace, typemapper.ToLower)
+
+			result, err := mapper.Map("order", []string{" DESC "})
+			require.NoError(t, err)
+			require.Equal(t, "desc", result)
+
+			failing := typemapper.Transform(
+				slug,
+				func(value string) (string, error) {
+					return "", fmt.Errorf("failed")
+				},
+			)
+			require.EqualError(t, failing.ValidateValues([]string{"a"}), "transform #0: failed")
+		},
+	)
+
+	t.Run(
+		"Chain",
+		func(t *testing.T) {
+			mapper := typemapper.Chain(
+				scalar.Int(),
+				func(field string, value interface{}) (interface{}, error) {
+					return value.(int) * 2, nil
+				},
+				func(field string, value interface{}) (interface{}, error) {
+					if value.(int) > 10 {
+						return nil, fmt.Errorf("too big")
+					}
+					return value, nil
+				},
+			)
+
+			result, err := mapper.Map("field", []string{"5"})
+			require.NoError(t, err)
+			require.Equal(t, 10, result)
+
+			_, err = mapper.Map("field", []string{"6"})
+			require.EqualError(t, err, "#2: too big")
+
+			_, err = typemapper.Chain(typemapper.Named("int", scalar.Int())).Map("field", []string{"x"})
+			require.ErrorIs(t, err, scalar.BadSyntax)
+			require.Contains(t, err.Error(), "int: ")
+		},
+	)
+
+	t.Run(
+		"FirstOf",
+		func(t *testing.T) {
+			mapper := typemapper.FirstOf(
+				typemapper.Named("uuid", scalar.UUIDValue()),
+				typemapper.Named("slug", slug),
+			)
+
+			result, err := mapper.Map("id", []string{"123e4567-e89b-12d3-a456-426614174000"})
+			require.NoError(t, err)
+			require.IsType(t, scalar.UUID{}, result)
+
+			result, err = mapper.Map("id", []string{"my-post"})
+			require.NoError(t, err)
+			require.Equal(t, "my-post", result)
+
+			_, err = mapper.Map("id", []string{"my post"})
+			var firstOfErr typemapper.FirstOfError
+			require.ErrorAs(t, err, &firstOfErr)
+			require.Len(t, firstOfErr, 2)
+			require.Equal(t, "uuid", firstOfErr[0].Branch)
+			require.ErrorIs(t, err, scalar.BadSyntax)
+			require.Contains(t, err.Error(), "slug: bad slug")
+
+			var branchErr *typemapper.BranchError
+			require.ErrorAs(t, err, &branchErr)
+			require.Equal(t, "uuid", branchErr.Branch)
+
+			require.NoError(t, mapper.ValidateValues([]string{""}))
+			require.Error(t, typemapper.FirstOf(slug).ValidateValues([]string{""}))
+		},
+	)
+
+	t.Run(
+		"Optional",
+		func(t *testing.T) {
+			mapper := typemapper.Optional(scalar.Int())
+
+			for _, values := range [][]string{nil, {""}} {
+				require.NoError(t, mapper.ValidateValues(values))
+				result, err := mapper.Map("field", values)
+				require.NoError(t, err)
+				require.Nil(t, result)
+			}
+
+			result, err := mapper.Map("field", []string{"1"})
+			require.NoError(t, err)
+			require.Equal(t, 1, result)
+		},
+	)
+
+	t.Run(
+		"ForEach",
+		func(t *testing.T) {
+			mapper := typemapper.ForEach(scalar.Int())
+
+			require.NoError(t, mapper.ValidateValues([]string{"1", "2"}))
+			result, err := mapper.Map("field", []string{"1", "2"})
+			require.NoError(t, err)
+			require.Equal(t, []interface{}{1, 2}, result)
+
+			_, err = mapper.Map("field", []string{"1", "x"})
+			require.ErrorIs(t, err, scalar.BadSyntax)
+			require.Contains(t, err.Error(), `value #1 "x"`)
+		},
+	)
+
+	t.Run(
+		"Context",
+		func(t *testing.T) {
+			type key struct{}
+			contextMapper := typemapper.NewCustomQueryTypeBuilder().
+				SetContextTypeMapperFunc(
+					func(ctx context.Context, field string, values []string) (interface{}, error) {
+						return ctx.Value(key{}), nil
+					},
+				).
+				MustBuild()
+
+			ctx := context.WithValue(context.Background(), key{}, "value")
+			for _, mapper := range []typemapper.QueryTypeMapper{
+				typemapper.Transform(contextMapper),
+				typemapper.Chain(contextMapper),
+				typemapper.FirstOf(contextMapper),
+				typemapper.Optional(contextMapper),
+				typemapper.Named("name", contextMapper),
+			} {
+				result, err := typemapper.MapContext(ctx, mapper, "field", []string{"1"})
+				require.NoError(t, err)
+				require.Equal(t, "value", result)
+			}
+		},
+	)
+}
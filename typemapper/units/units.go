@@ -0,0 +1,292 @@
+// Package units provide typemapper.QueryTypeMapper of values with units
+// that are converted to canonical numbers by unit tables
+//
+//	size=10MB      -> int64 bytes
+//	timeout=1h30m  -> time.Duration
+//	distance=5km   -> float64 meters
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+)
+
+var (
+	// MissingUnit returned if value have no unit and default unit is not set
+	MissingUnit = errors.New("Missing unit")
+
+	// UnknownUnit returned if unit is not in table
+	UnknownUnit = errors.New("Unknown unit")
+
+	// Overflow returned if value is too big for result type
+	Overflow = errors.New("Value overflow")
+
+	// NotWhole returned if value can't be represented
+	// by whole number of canonical units
+	NotWhole = errors.New("Not whole number of units")
+
+	// Negative returned for negative values if they are not allowed
+	Negative = errors.New("Negative value")
+)
+
+// Table is factors of units to canonical unit as decimal strings
+//
+//	Table{"m": "1", "km": "1000", "mm": "0.001"}
+type Table map[string]string
+
+var (
+	// BytesTable is table of SI and IEC sizes in bytes
+	BytesTable = Table{
+		"B":   "1",
+		"KB":  "1000",
+		"MB":  "1000000",
+		"GB":  "1000000000",
+		"TB":  "1000000000000",
+		"PB":  "1000000000000000",
+		"KiB": "1024",
+		"MiB": "1048576",
+		"GiB": "1073741824",
+		"TiB": "1099511627776",
+		"PiB": "1125899906842624",
+	}
+
+	// DurationTable is table of durations in nanoseconds
+	DurationTable = Table{
+		"ns": "1",
+		"us": "1000",
+		"µs": "1000",
+		"ms": "1000000",
+		"s":  "1000000000",
+		"m":  "60000000000",
+		"h":  "3600000000000",
+		"d":  "86400000000000",
+		"w":  "604800000000000",
+	}
+
+	// DistanceTable is table of distances in meters
+	DistanceTable = Table{
+		"mm": "0.001",
+		"cm": "0.01",
+		"m":  "1",
+		"km": "1000",
+		"in": "0.0254",
+		"ft": "0.3048",
+		"yd": "0.9144",
+		"mi": "1609.344",
+	}
+)
+
+// UnitMapper map value with units to canonical number
+//
+// Value is sequence of numbers with units that are summed
+//
+//	1h30m  1.5GB  5 km
+type UnitMapper struct {
+	table map[string]*big.Rat
+
+	defaultUnit string
+
+	negative bool
+
+	convert func(value *big.Rat) (interface{}, error)
+}
+
+// Int return mapper of value in units of table to int64
+func Int(table Table) *UnitMapper {
+	return newUnitMapper(table, toInt)
+}
+
+// Float return mapper of value in units of table to float64
+func Float(table Table) *UnitMapper {
+	return newUnitMapper(table, toFloat)
+}
+
+// Bytes return mapper of size to int64 bytes
+func Bytes() *UnitMapper {
+	return Int(BytesTable)
+}
+
+// Duration return mapper of duration to time.Duration,
+// unlike time.ParseDuration it support days and weeks
+func Duration() *UnitMapper {
+	return newUnitMapper(
+		DurationTable,
+		func(value *big.Rat) (interface{}, error) {
+			nanoseconds, err := toInt(value)
+			if err != nil {
+				return nil, err
+			}
+			return time.Duration(nanoseconds.(int64)), nil
+		},
+	)
+}
+
+// Distance return mapper of distance to float64 meters
+func Distance() *UnitMapper {
+	return Float(DistanceTable)
+}
+
+// newUnitMapper panic if factor of table is not a decimal number
+func newUnitMapper(table Table, convert func(value *big.Rat) (interface{}, error)) *UnitMapper {
+	u := &UnitMapper{
+		table:   make(map[string]*big.Rat, len(table)),
+		convert: convert,
+	}
+
+	for unit, factor := range table {
+		rat, ok := new(big.Rat).SetString(factor)
+		if !ok {
+			panic(fmt.Sprintf("units: bad factor %q of unit %q", factor, unit))
+		}
+		u.table[unit] = rat
+	}
+
+	return u
+}
+
+// SetDefaultUnit set unit of value without unit
+func (u *UnitMapper) SetDefaultUnit(unit string) *UnitMapper {
+	u.defaultUnit = unit
+	return u
+}
+
+// SetAllowNegative allow values with leading minus
+func (u *UnitMapper) SetAllowNegative(allow bool) *UnitMapper {
+	u.negative = allow
+	return u
+}
+
+// Units return units of table
+func (u *UnitMapper) Units() []string {
+	units := make([]string, 0, len(u.table))
+	for unit := range u.table {
+		units = append(units, unit)
+	}
+	sortUnits(units, u.table)
+
+	return units
+}
+
+// sortUnits sort units by factor and then by name
+func sortUnits(units []string, table map[string]*big.Rat) {
+	sort.Slice(
+		units,
+		func(i, j int) bool {
+			if cmp := table[units[i]].Cmp(table[units[j]]); cmp != 0 {
+				return cmp < 0
+			}
+			return units[i] < units[j]
+		},
+	)
+}
+
+// Can validate values count or type if needed(string, json, or users type)
+func (u *UnitMapper) ValidateValues(values []string) error {
+	_, err := scalar.OneValue(values)
+	return err
+}
+
+// Can validateField format
+func (u *UnitMapper) ValidateField(field string) error {
+	return nil
+}
+
+func (u *UnitMapper) Map(field string, values []string) (interface{}, error) {
+	value, err := scalar.OneValue(values)
+	if err != nil {
+		return nil, err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, scalar.EmptyValue
+	}
+
+	sum, err := u.parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.convert(sum)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, value)
+	}
+
+	return result, nil
+}
+
+// parse return sum of numbers with units in canonical unit
+func (u *UnitMapper) parse(value string) (*big.Rat, error) {
+	var (
+		sum  = new(big.Rat)
+		rest = value
+		neg  bool
+	)
+
+	if strings.HasPrefix(rest, "-") {
+		if !u.negative {
+			return nil, fmt.Errorf("%w: %q", Negative, value)
+		}
+		neg, rest = true, rest[1:]
+	}
+
+	for first := true; rest != ""; first = false {
+		number := rest[:len(rest)-len(strings.TrimLeft(rest, "0123456789."))]
+		rest = strings.TrimLeftFunc(rest[len(number):], unicode.IsSpace)
+
+		unit := rest[:len(rest)-len(strings.TrimLeftFunc(rest, unicode.IsLetter))]
+		rest = strings.TrimLeftFunc(rest[len(unit):], unicode.IsSpace)
+
+		amount, ok := new(big.Rat).SetString(number)
+		if number == "" || strings.HasPrefix(number, ".") || strings.HasSuffix(number, ".") || !ok {
+			return nil, fmt.Errorf("%w: %q is not a number with unit", scalar.BadSyntax, value)
+		}
+
+		if unit == "" {
+			if !first || rest != "" || u.defaultUnit == "" {
+				return nil, fmt.Errorf("%w in %q, expect one of %s", MissingUnit, value, strings.Join(u.Units(), ", "))
+			}
+			unit = u.defaultUnit
+		}
+
+		factor, find := u.table[unit]
+		if !find {
+			return nil, fmt.Errorf("%w %q in %q, expect one of %s", UnknownUnit, unit, value, strings.Join(u.Units(), ", "))
+		}
+
+		sum.Add(sum, amount.Mul(amount, factor))
+	}
+
+	if neg {
+		sum.Neg(sum)
+	}
+
+	return sum, nil
+}
+
+func toInt(value *big.Rat) (interface{}, error) {
+	if !value.IsInt() {
+		return nil, NotWhole
+	} else if !value.Num().IsInt64() {
+		return nil, Overflow
+	}
+
+	return value.Num().Int64(), nil
+}
+
+func toFloat(value *big.Rat) (interface{}, error) {
+	result, _ := value.Float64()
+	if math.IsInf(result, 0) {
+		return nil, Overflow
+	}
+
+	return result, nil
+}
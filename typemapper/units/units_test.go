@@ -0,0 +1,103 @@
+package units_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0B1t322/QueryParser/typemapper/scalar"
+	"github.com/0B1t322/QueryParser/typemapper/units"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunc_Units(t *testing.T) {
+	t.Run(
+		"Bytes",
+		func(t *testing.T) {
+			for value, expect := range map[string]int64{
+				"10MB":    10000000,
+				"1.5KiB":  1536,
+				"1 GiB":   1073741824,
+				"1KB 24B": 1024,
+			} {
+				result, err := units.Bytes().Map("size", []string{value})
+				require.NoError(t, err, value)
+				require.Equal(t, expect, result, value)
+			}
+		},
+	)
+
+	t.Run(
+		"Duration",
+		func(t *testing.T) {
+			for value, expect := range map[string]time.Duration{
+				"1h30m": 90 * time.Minute,
+				"2d":    48 * time.Hour,
+				"1.5s":  1500 * time.Millisecond,
+				"250ms": 250 * time.Millisecond,
+			} {
+				result, err := units.Duration().Map("timeout", []string{value})
+				require.NoError(t, err, value)
+				require.Equal(t, expect, result, value)
+			}
+
+			result, err := units.Duration().SetAllowNegative(true).Map("offset", []string{"-1h"})
+			require.NoError(t, err)
+			require.Equal(t, -time.Hour, result)
+		},
+	)
+
+	t.Run(
+		"Distance",
+		func(t *testing.T) {
+			result, err := units.Distance().Map("distance", []string{"5km"})
+			require.NoError(t, err)
+			require.Equal(t, 5000.0, result)
+
+			result, err = units.Distance().SetDefaultUnit("m").Map("distance", []string{"12.5"})
+			require.NoError(t, err)
+			require.Equal(t, 12.5, result)
+		},
+	)
+
+	t.Run(
+		"CustomTable",
+		func(t *testing.T) {
+			mapper := units.Int(units.Table{"g": "1", "kg": "1000", "t": "1000000"})
+			require.Equal(t, []string{"g", "kg", "t"}, mapper.Units())
+
+			result, err := mapper.Map("weight", []string{"1.2t"})
+			require.NoError(t, err)
+			require.Equal(t, int64(1200000), result)
+
+			require.Panics(t, func() { units.Int(units.Table{"x": "one"}) })
+		},
+	)
+
+	t.Run(
+		"Errors",
+		func(t *testing.T) {
+			for value, expect := range map[string]error{
+				"10":         units.MissingUnit,
+				"10MB 5":     units.MissingUnit,
+				"10XB":       units.UnknownUnit,
+				"0.5B":       units.NotWhole,
+				"9999999PiB": units.Overflow,
+				"-1MB":       units.Negative,
+				"MB":         scalar.BadSyntax,
+				"1..5MB":     scalar.BadSyntax,
+				"":           scalar.EmptyValue,
+			} {
+				_, err := units.Bytes().Map("size", []string{value})
+				require.True(t, errors.Is(err, expect), "%s: %v", value, err)
+			}
+
+			_, err := units.Duration().Map("timeout", []string{"3000000h"})
+			require.True(t, errors.Is(err, units.Overflow))
+
+			require.True(t, errors.Is(units.Bytes().ValidateValues([]string{"1B", "2B"}), scalar.ExpectOneValue))
+			_, mapErr := units.Bytes().Map("field", nil)
+			require.True(t, errors.Is(mapErr, scalar.ExpectOneValue))
+		},
+	)
+}
@@ -1,7 +1,7 @@
 package validator
 
 import (
-	
+	"context"
 )
 
 // Validations describe map of query valiues with their validations
@@ -37,4 +37,18 @@ func MergeValidationsFunc(funcs ...ValidateFunc) ValidateFunc {
 
 		return nil
 	}
-}
\ No newline at end of file
+}
+
+// CapturesValidateFunc is ValidateFunc that also take
+// named capture groups of regex key that matched field
+type CapturesValidateFunc func(value interface{}, captures map[string]string) error
+
+// ContextValidateFunc is ValidateFunc that take context of parse
+type ContextValidateFunc func(ctx context.Context, value interface{}) error
+
+// ToContext adapt ValidateFunc to ContextValidateFunc that ignore context
+func (f ValidateFunc) ToContext() ContextValidateFunc {
+	return func(ctx context.Context, value interface{}) error {
+		return f(value)
+	}
+}